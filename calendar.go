@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dateLayout = "2006-01-02"
+
+// calendar knows non-working days of a team.
+type calendar struct {
+	// dates maps YYYY-MM-DD dates to holiday names.
+	dates map[string]string
+	// yearly holds holidays recurring every year.
+	yearly []yearlyHoliday
+}
+
+// yearlyHoliday recurs on the same month and day every year from the year of
+// its first occurrence, until the optional last date.
+type yearlyHoliday struct {
+	monthDay string // MM-DD
+	first    string // YYYY-MM-DD
+	until    string // YYYY-MM-DD, empty if the holiday recurs forever
+	name     string
+}
+
+// holiday reports whether the date of t is a non-working day, with the holiday name if known.
+func (c *calendar) holiday(t time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	date := t.Format(dateLayout)
+	if name, ok := c.dates[date]; ok {
+		return name, true
+	}
+
+	for _, h := range c.yearly {
+		if date[5:] == h.monthDay && date >= h.first && (h.until == "" || date <= h.until) {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// addDays adds count consecutive days starting from start.
+func (c *calendar) addDays(start time.Time, count int, name string) {
+	for i := 0; i < count; i++ {
+		c.dates[start.AddDate(0, 0, i).Format(dateLayout)] = name
+	}
+}
+
+// loadCalendar builds the calendar from the iCalendar file, the holidays
+// file and the inline holidays. It returns nil if none is configured. Times of
+// iCalendar events are converted to location, nil for UTC, before taking their
+// dates.
+func loadCalendar(cfg ConfigCalendar, location *time.Location) (*calendar, error) {
+	if cfg.ICSFile == "" && cfg.HolidaysFile == "" && len(cfg.Holidays) == 0 {
+		return nil, nil
+	}
+
+	c := &calendar{dates: make(map[string]string)}
+
+	if cfg.ICSFile != "" {
+		f, err := os.Open(cfg.ICSFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ics_file: %w", err)
+		}
+		defer f.Close()
+
+		if err := c.parseICS(f, location); err != nil {
+			return nil, fmt.Errorf("error parsing ics_file: %w", err)
+		}
+	}
+
+	holidays := cfg.Holidays
+
+	if cfg.HolidaysFile != "" {
+		if cfg.Country == "" {
+			return nil, fmt.Errorf("country is required with holidays_file")
+		}
+
+		data, err := os.ReadFile(cfg.HolidaysFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading holidays_file: %w", err)
+		}
+
+		var byCountry map[string][]ConfigHoliday
+		if err := yaml.Unmarshal(data, &byCountry); err != nil {
+			return nil, fmt.Errorf("error parsing holidays_file: %w", err)
+		}
+
+		countryHolidays, ok := byCountry[cfg.Country]
+		if !ok {
+			return nil, fmt.Errorf("country %q not found in holidays_file", cfg.Country)
+		}
+		holidays = append(countryHolidays, holidays...)
+	}
+
+	for _, h := range holidays {
+		date, err := time.Parse(dateLayout, h.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q, must be in YYYY-MM-DD format", h.Date)
+		}
+		c.addDays(date, 1, h.Name)
+	}
+
+	return c, nil
+}
+
+// icsEvent holds properties of a VEVENT relevant for non-working days.
+type icsEvent struct {
+	start, end     icsValue
+	summary, rrule string
+}
+
+// icsValue is a DATE or DATE-TIME value with its TZID parameter, if any.
+type icsValue struct {
+	value, tzid string
+}
+
+// parseICS adds days covered by events of an iCalendar document. Events
+// lasting whole days end on the day before DTEND, as defined by RFC 5545.
+// Times are converted to location before taking their dates. Yearly
+// recurrence rules are supported, other recurrences are treated as single
+// events.
+func (c *calendar) parseICS(r io.Reader, location *time.Location) error {
+	if location == nil {
+		location = time.UTC
+	}
+
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return err
+	}
+
+	var event *icsEvent
+	for _, line := range lines {
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(property, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event = &icsEvent{}
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				if err := c.addICSEvent(event, location); err != nil {
+					return err
+				}
+				event = nil
+			}
+		case "DTSTART":
+			if event != nil {
+				event.start = icsValue{value: value, tzid: icsTZID(params)}
+			}
+		case "DTEND":
+			if event != nil {
+				event.end = icsValue{value: value, tzid: icsTZID(params)}
+			}
+		case "SUMMARY":
+			if event != nil {
+				event.summary = unescapeICSText(value)
+			}
+		case "RRULE":
+			if event != nil {
+				event.rrule = value
+			}
+		}
+	}
+	return nil
+}
+
+func (c *calendar) addICSEvent(event *icsEvent, location *time.Location) error {
+	startTime, err := parseICSTime(event.start, location)
+	if err != nil {
+		return fmt.Errorf("invalid DTSTART of %q: %w", event.summary, err)
+	}
+	start := icsDate(startTime)
+
+	days := 1
+	if event.end.value != "" {
+		endTime, err := parseICSTime(event.end, location)
+		if err != nil {
+			return fmt.Errorf("invalid DTEND of %q: %w", event.summary, err)
+		}
+
+		// DTEND is exclusive for dates and for date-times at midnight.
+		days = int(icsDate(endTime).Sub(start).Hours()/24 + 0.5)
+		if hour, minute, second := endTime.Clock(); hour != 0 || minute != 0 || second != 0 {
+			days++
+		}
+		days = max(days, 1)
+	}
+
+	rule := parseICSRule(event.rrule)
+	if rule["FREQ"] != "YEARLY" {
+		c.addDays(start, days, event.summary)
+		return nil
+	}
+
+	var until string
+	if rule["UNTIL"] != "" {
+		untilTime, err := parseICSTime(icsValue{value: rule["UNTIL"], tzid: event.start.tzid}, location)
+		if err != nil {
+			return fmt.Errorf("invalid UNTIL of %q: %w", event.summary, err)
+		}
+		until = icsDate(untilTime).Format(dateLayout)
+	}
+
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i).Format(dateLayout)
+		c.yearly = append(c.yearly, yearlyHoliday{monthDay: day[5:], first: day, until: until, name: event.summary})
+	}
+	return nil
+}
+
+// unfoldICSLines splits the document into content lines, joining lines folded with leading whitespace.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSTime parses a DATE or DATE-TIME value and returns it in location.
+// Date-times are in UTC with the Z suffix, in the time zone of the TZID
+// parameter, or floating, i.e. in location, without either. Dates are
+// midnight in location.
+func parseICSTime(v icsValue, location *time.Location) (time.Time, error) {
+	if len(v.value) == len("20060102") {
+		return time.ParseInLocation("20060102", v.value, location)
+	}
+
+	if strings.HasSuffix(v.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", v.value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date-time %q", v.value)
+		}
+		return t.In(location), nil
+	}
+
+	timeLocation := location
+	if v.tzid != "" {
+		var err error
+		timeLocation, err = time.LoadLocation(v.tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", v.tzid)
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", v.value, timeLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time %q", v.value)
+	}
+	return t.In(location), nil
+}
+
+// icsDate returns the date of t as midnight UTC, so that days between dates
+// are not affected by daylight saving time.
+func icsDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// icsTZID returns the TZID parameter of a property, e.g. of
+// DTSTART;TZID=Europe/Warsaw, with optional quotes removed.
+func icsTZID(params string) string {
+	for _, param := range strings.Split(params, ";") {
+		if name, value, ok := strings.Cut(param, "="); ok && strings.EqualFold(name, "TZID") {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// parseICSRule splits a recurrence rule into its parts, e.g. FREQ=YEARLY;UNTIL=20301231.
+func parseICSRule(rule string) map[string]string {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		if name, value, ok := strings.Cut(part, "="); ok {
+			parts[strings.ToUpper(name)] = strings.ToUpper(value)
+		}
+	}
+	return parts
+}
+
+var icsTextReplacer = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, " ", `\N`, " ")
+
+func unescapeICSText(s string) string {
+	return icsTextReplacer.Replace(s)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCalendar(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(dateLayout, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("nothing configured", func(t *testing.T) {
+		c, err := loadCalendar(ConfigCalendar{}, nil)
+		require.NoError(t, err)
+		assert.Nil(t, c)
+
+		_, ok := c.holiday(date("2024-12-25"))
+		assert.False(t, ok)
+	})
+
+	t.Run("ics file", func(t *testing.T) {
+		c, err := loadCalendar(ConfigCalendar{ICSFile: "testdata/holidays.ics"}, nil)
+		require.NoError(t, err)
+
+		tests := []struct {
+			date    string
+			holiday bool
+			name    string
+		}{
+			{"2020-12-24", false, ""},
+			{"2020-12-25", true, "Christmas, Boxing Day"},
+			{"2024-12-26", true, "Christmas, Boxing Day"},
+			{"2024-12-27", false, ""},
+			{"2019-12-25", false, ""},
+			{"2024-09-15", false, ""},
+			{"2024-09-16", true, "Team offsite in a very long summary line that is folded by the exporting application"},
+			{"2024-09-18", true, "Team offsite in a very long summary line that is folded by the exporting application"},
+			{"2024-09-19", false, ""},
+			{"2022-07-04", true, "Independence Day"},
+			{"2023-07-04", false, ""},
+		}
+
+		for _, tt := range tests {
+			name, ok := c.holiday(date(tt.date))
+			assert.Equal(t, tt.holiday, ok, tt.date)
+			assert.Equal(t, tt.name, name, tt.date)
+		}
+	})
+
+	t.Run("ics times in cron timezone", func(t *testing.T) {
+		warsaw, err := time.LoadLocation("Europe/Warsaw")
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "events.ics")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"DTSTART:20261224T230000Z",
+			"DTEND:20261225T230000Z",
+			"SUMMARY:Christmas",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"DTSTART;TZID=America/New_York:20261230T200000",
+			"DTEND;TZID=America/New_York:20261230T220000",
+			"SUMMARY:Year-end party",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"DTSTART:20260703T233000",
+			"DTEND:20260704T000000",
+			"SUMMARY:Late release",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")), 0o644))
+
+		c, err := loadCalendar(ConfigCalendar{ICSFile: path}, warsaw)
+		require.NoError(t, err)
+
+		tests := []struct {
+			date    string
+			holiday bool
+		}{
+			{"2026-12-24", false},
+			{"2026-12-25", true},
+			{"2026-12-26", false},
+			{"2026-12-30", false},
+			{"2026-12-31", true},
+			{"2026-07-03", true},
+			{"2026-07-04", false},
+		}
+		for _, tt := range tests {
+			_, ok := c.holiday(date(tt.date))
+			assert.Equal(t, tt.holiday, ok, tt.date)
+		}
+
+		// Without a cron timezone, UTC dates are used.
+		c, err = loadCalendar(ConfigCalendar{ICSFile: path}, nil)
+		require.NoError(t, err)
+
+		_, ok := c.holiday(date("2026-12-24"))
+		assert.True(t, ok)
+	})
+
+	t.Run("holidays file and inline holidays", func(t *testing.T) {
+		c, err := loadCalendar(ConfigCalendar{
+			HolidaysFile: "testdata/holidays.yaml",
+			Country:      "PL",
+			Holidays:     []ConfigHoliday{{Date: "2024-12-24", Name: "Christmas Eve"}},
+		}, nil)
+		require.NoError(t, err)
+
+		name, ok := c.holiday(date("2024-11-01"))
+		assert.True(t, ok)
+		assert.Equal(t, "All Saints' Day", name)
+
+		_, ok = c.holiday(date("2024-11-11"))
+		assert.True(t, ok)
+
+		name, ok = c.holiday(date("2024-12-24"))
+		assert.True(t, ok)
+		assert.Equal(t, "Christmas Eve", name)
+
+		_, ok = c.holiday(date("2024-10-03"))
+		assert.False(t, ok)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := loadCalendar(ConfigCalendar{HolidaysFile: "testdata/holidays.yaml"}, nil)
+		assert.EqualError(t, err, "country is required with holidays_file")
+
+		_, err = loadCalendar(ConfigCalendar{HolidaysFile: "testdata/holidays.yaml", Country: "FR"}, nil)
+		assert.EqualError(t, err, `country "FR" not found in holidays_file`)
+
+		_, err = loadCalendar(ConfigCalendar{Holidays: []ConfigHoliday{{Date: "25.12.2024"}}}, nil)
+		assert.EqualError(t, err, `invalid holiday date "25.12.2024", must be in YYYY-MM-DD format`)
+
+		_, err = loadCalendar(ConfigCalendar{ICSFile: "testdata/missing.ics"}, nil)
+		assert.ErrorContains(t, err, "error reading ics_file")
+
+		path := filepath.Join(t.TempDir(), "events.ics")
+		require.NoError(t, os.WriteFile(path, []byte("BEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20260101T100000\nSUMMARY:Launch\nEND:VEVENT\n"), 0o644))
+		_, err = loadCalendar(ConfigCalendar{ICSFile: path}, nil)
+		assert.EqualError(t, err, `error parsing ics_file: invalid DTSTART of "Launch": unknown TZID "Mars/Olympus"`)
+	})
+}
+
+func TestWorkingTeams(t *testing.T) {
+	c, err := loadCalendar(ConfigCalendar{Holidays: []ConfigHoliday{{Date: "2024-12-25"}}}, nil)
+	require.NoError(t, err)
+
+	backend := &Config{team: "backend", calendar: c}
+	mobile := &Config{team: "mobile"}
+
+	holiday := time.Date(2024, 12, 25, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []*Config{mobile}, workingTeams([]*Config{backend, mobile}, holiday))
+
+	workday := time.Date(2024, 12, 27, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []*Config{backend, mobile}, workingTeams([]*Config{backend, mobile}, workday))
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Colors of summary sections, used by platforms supporting colored blocks.
+var stateColors = map[MergeRequestState]int{
+	StateNeedsReview:     0x1f75cb,
+	StateWaitingOnAuthor: 0xe9be74,
+	StateReadyToMerge:    0x108548,
+}
+
+// Length limits of Discord embeds, see
+// https://discord.com/developers/docs/resources/message#embed-object-embed-limits.
+const (
+	discordMaxDescriptionRunes = 4096
+	discordMaxEmbedsRunes      = 6000
+	discordMaxEmbeds           = 10
+)
+
+// mattermostMaxMessageRunes is the length limit of a Mattermost post, which
+// is also applied to the attachments of a message in total.
+const mattermostMaxMessageRunes = 16383
+
+var chatMarkdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "[", `\[`, "]", `\]`, "|", `\|`,
+)
+
+// escapeChatMarkdown escapes text for Discord and Mattermost Markdown.
+func escapeChatMarkdown(s string) string {
+	return chatMarkdownReplacer.Replace(s)
+}
+
+// mergeRequestDetails returns "label: value" lines describing a merge request
+// below its title, as plain text.
+func mergeRequestDetails(summary *Summary, mr *MergeRequestView) []string {
+	catalog := summary.catalog
+
+	var created string
+	if summary.RelativeDates {
+		created = catalog.T("opened") + ": " + catalog.T("ago", humanizeAge(mr.CreatedAt, summary.Now, summary.businessDays, catalog))
+	} else {
+		created = catalog.T("created_at") + ": " + mr.CreatedAt.Format(catalog.T("date_layout"))
+	}
+
+	approvedBy := catalog.T("none")
+	if len(mr.ApprovedBy) > 0 {
+		approvedBy = strings.Join(mr.ApprovedBy, ", ")
+	}
+
+	details := []string{
+		catalog.T("author") + ": " + mr.Author,
+		created,
+		catalog.T("approved_by") + ": " + approvedBy,
+	}
+	if mr.HasUnresolvedDiscussions {
+		details = append(details, catalog.T("unresolved_discussions"))
+	}
+	if mr.AuthorAway {
+		details = append(details, catalog.T("author_away"))
+	}
+	if len(mr.AwayReviewers) > 0 {
+		details = append(details, catalog.T("reviewer_away", strings.Join(mr.AwayReviewers, ", ")))
+	}
+	if mr.SuggestedReviewer != "" {
+		details = append(details, catalog.T("suggested_reviewer", mr.SuggestedReviewer))
+	}
+	return details
+}
+
+// chatMarkdownBlocks renders merge requests of a section as Markdown list
+// items understood by both Discord and Mattermost, one block per merge request.
+// Group headers are prepended to the first merge request of the group.
+func chatMarkdownBlocks(summary *Summary, section *SummarySection) []string {
+	var blocks []string
+	for _, group := range section.Groups {
+		var header string
+		if group.Name != "" {
+			header = fmt.Sprintf("**%s**\n", escapeChatMarkdown(group.Name))
+		}
+		for _, mr := range group.MergeRequests {
+			blocks = append(blocks, fmt.Sprintf("%s- [!%d %s](%s)\n  %s", header, mr.IID, escapeChatMarkdown(mr.Title), mr.URL,
+				escapeChatMarkdown(strings.Join(mergeRequestDetails(summary, mr), " · "))))
+			header = ""
+		}
+	}
+	return blocks
+}
+
+// chatAttachment is a titled part of a section, rendered by Discord as an
+// embed and by Mattermost as a message attachment.
+type chatAttachment struct {
+	section *SummarySection
+	title   string
+	text    string
+}
+
+// chatMessages renders the sections as attachments whose text has at most
+// maxTextRunes, splitting long sections between merge requests. Attachments
+// are packed into messages with at most maxMessageRunes of titles and texts
+// and at most maxAttachments attachments each, 0 meaning no limit.
+func chatMessages(summary *Summary, maxTextRunes, maxMessageRunes, maxAttachments int) [][]chatAttachment {
+	var attachments []chatAttachment
+	for _, section := range summary.Sections {
+		title := fmt.Sprintf("%s (%d)", section.Title, section.Count)
+
+		blocks := chatMarkdownBlocks(summary, section)
+		sizes := make([]int, len(blocks))
+		for i, block := range blocks {
+			sizes[i] = len([]rune(block))
+		}
+
+		for _, chunk := range splitBlocks(sizes, maxTextRunes) {
+			attachments = append(attachments, chatAttachment{
+				section: section,
+				title:   title,
+				text:    truncateRunes(strings.Join(blocks[chunk[0]:chunk[1]], "\n"), maxTextRunes),
+			})
+		}
+	}
+
+	messages := [][]chatAttachment{nil}
+	size := 0
+	for _, attachment := range attachments {
+		last := len(messages) - 1
+		attachmentSize := len([]rune(attachment.title)) + len([]rune(attachment.text))
+		if len(messages[last]) > 0 &&
+			(size+attachmentSize > maxMessageRunes || maxAttachments > 0 && len(messages[last]) == maxAttachments) {
+			messages = append(messages, nil)
+			last, size = last+1, 0
+		}
+		messages[last] = append(messages[last], attachment)
+		size += attachmentSize
+	}
+
+	return messages
+}
+
+// omittedMarkdown renders the note about merge requests left out, empty if there are none.
+func omittedMarkdown(summary *Summary) string {
+	if summary.Omitted == 0 {
+		return ""
+	}
+	text := summary.catalog.T("and_more", summary.Omitted)
+	if summary.MoreURL != "" {
+		return fmt.Sprintf("[%s](%s)", escapeChatMarkdown(text), summary.MoreURL)
+	}
+	return escapeChatMarkdown(text)
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with an ellipsis.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// runeCount returns the length of s in runes.
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// fitTitle renders a block with render and shortens the title until the size
+// of the block is within limit, or the title cannot be shortened further.
+// Renderers never take fewer size units for a title than it has runes, so
+// each pass cuts at least the excess.
+func fitTitle[T any](title string, limit int, size func(T) int, render func(title string) T) T {
+	block := render(title)
+	for excess := size(block) - limit; excess > 0; excess = size(block) - limit {
+		runes := []rune(title)
+		if len(runes) <= 1 {
+			break
+		}
+		title = truncateRunes(title, max(len(runes)-excess, 1))
+		block = render(title)
+	}
+	return block
+}
+
+// postJSON posts the payload to a webhook URL and fails on non-2xx responses.
+func postJSON(ctx context.Context, url string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// chatDestination renders the summary with platform-specific payloads and
+// posts them to an incoming webhook, one message per payload.
+type chatDestination struct {
+	name       string
+	config     *Config
+	webhookURL string
+	language   string
+	payloads   func(summary *Summary) []any
+}
+
+func (d *chatDestination) Name() string {
+	return d.name
+}
+
+func (d *chatDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	catalog, err := catalogFor(d.language)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range d.payloads(buildSummary(mrs, d.config, catalog, now)) {
+		if d.config.DryRun {
+			if err := writeDryRunPayload(d.config.DryRunOutput, payload); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := postJSON(ctx, d.webhookURL, payload); err != nil {
+			return fmt.Errorf("error posting to %s webhook: %w", d.name, err)
+		}
+	}
+
+	if d.config.DryRun {
+		return nil
+	}
+	log.Printf("Successfully sent merge request summary to %s.", d.name)
+	return nil
+}
+
+func newDiscordDestination(config *Config) *chatDestination {
+	return &chatDestination{
+		name:       "Discord",
+		config:     config,
+		webhookURL: config.Discord.WebhookURL,
+		language:   config.Discord.Language,
+		payloads: func(summary *Summary) []any {
+			var payloads []any
+			for _, msg := range discordPayloads(summary, config.Discord.Username) {
+				payloads = append(payloads, msg)
+			}
+			return payloads
+		},
+	}
+}
+
+func newMattermostDestination(config *Config) *chatDestination {
+	return &chatDestination{
+		name:       "Mattermost",
+		config:     config,
+		webhookURL: config.Mattermost.WebhookURL,
+		language:   config.Mattermost.Language,
+		payloads: func(summary *Summary) []any {
+			var payloads []any
+			for _, msg := range mattermostPayloads(summary, config.Mattermost.Channel, config.Mattermost.Username) {
+				payloads = append(payloads, msg)
+			}
+			return payloads
+		},
+	}
+}
+
+func newGoogleChatDestination(config *Config) *chatDestination {
+	return &chatDestination{
+		name:       "Google Chat",
+		config:     config,
+		webhookURL: config.GoogleChat.WebhookURL,
+		language:   config.GoogleChat.Language,
+		payloads:   func(summary *Summary) []any { return []any{googleChatPayload(summary)} },
+	}
+}
+
+type discordMessage struct {
+	Content  string         `json:"content,omitempty"`
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+// discordPayloads renders one embed per section, split into as many embeds
+// and messages as needed to stay within Discord's limits. The note about
+// omitted merge requests is the content of the first message.
+func discordPayloads(summary *Summary, username string) []*discordMessage {
+	var msgs []*discordMessage
+	for i, attachments := range chatMessages(summary, discordMaxDescriptionRunes, discordMaxEmbedsRunes, discordMaxEmbeds) {
+		msg := &discordMessage{Username: username}
+		if i == 0 {
+			msg.Content = omittedMarkdown(summary)
+		}
+		for _, attachment := range attachments {
+			msg.Embeds = append(msg.Embeds, discordEmbed{
+				Title:       attachment.title,
+				Description: attachment.text,
+				Color:       stateColors[attachment.section.State],
+			})
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+type mattermostMessage struct {
+	Text        string                 `json:"text,omitempty"`
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	Attachments []mattermostAttachment `json:"attachments"`
+}
+
+type mattermostAttachment struct {
+	Fallback string `json:"fallback"`
+	Color    string `json:"color"`
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+}
+
+// mattermostPayloads renders one attachment per section with a Markdown list
+// of merge requests, split into as many attachments and messages as needed to
+// stay within the post length limit. The note about omitted merge requests is
+// the text of the first message.
+func mattermostPayloads(summary *Summary, channel, username string) []*mattermostMessage {
+	var msgs []*mattermostMessage
+	for i, attachments := range chatMessages(summary, mattermostMaxMessageRunes, mattermostMaxMessageRunes, 0) {
+		msg := &mattermostMessage{Channel: channel, Username: username}
+		if i == 0 {
+			msg.Text = omittedMarkdown(summary)
+		}
+		for _, attachment := range attachments {
+			msg.Attachments = append(msg.Attachments, mattermostAttachment{
+				Fallback: attachment.title,
+				Color:    fmt.Sprintf("#%06x", stateColors[attachment.section.State]),
+				Title:    attachment.title,
+				Text:     attachment.text,
+			})
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+type googleChatMessage struct {
+	CardsV2 []googleChatCardWithID `json:"cardsV2"`
+}
+
+type googleChatCardWithID struct {
+	CardID string         `json:"cardId"`
+	Card   googleChatCard `json:"card"`
+}
+
+type googleChatCard struct {
+	Header   googleChatHeader    `json:"header"`
+	Sections []googleChatSection `json:"sections"`
+}
+
+type googleChatHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
+
+type googleChatSection struct {
+	Header  string             `json:"header"`
+	Widgets []googleChatWidget `json:"widgets"`
+}
+
+type googleChatWidget struct {
+	DecoratedText *googleChatDecoratedText `json:"decoratedText,omitempty"`
+	TextParagraph *googleChatTextParagraph `json:"textParagraph,omitempty"`
+}
+
+type googleChatDecoratedText struct {
+	TopLabel string `json:"topLabel,omitempty"`
+	Text     string `json:"text"`
+	WrapText bool   `json:"wrapText"`
+}
+
+type googleChatTextParagraph struct {
+	Text string `json:"text"`
+}
+
+// googleChatPayload renders a card with a section per summary section and
+// group. Card text supports a small subset of HTML.
+func googleChatPayload(summary *Summary) *googleChatMessage {
+	catalog := summary.catalog
+
+	card := googleChatCard{
+		Header: googleChatHeader{
+			Title:    catalog.T("report_title"),
+			Subtitle: catalog.T("generated_at") + ": " + summary.Now.Format(catalog.T("date_layout")),
+		},
+	}
+
+	for _, section := range summary.Sections {
+		for _, group := range section.Groups {
+			header := fmt.Sprintf("%s (%d)", section.Title, section.Count)
+			if group.Name != "" {
+				header += " · " + group.Name
+			}
+
+			chatSection := googleChatSection{Header: html.EscapeString(header)}
+			for _, mr := range group.MergeRequests {
+				text := fmt.Sprintf(`<a href="%s">!%d %s</a>`, html.EscapeString(mr.URL), mr.IID, html.EscapeString(mr.Title))
+				for _, detail := range mergeRequestDetails(summary, mr) {
+					text += "<br>" + html.EscapeString(detail)
+				}
+
+				chatSection.Widgets = append(chatSection.Widgets, googleChatWidget{
+					DecoratedText: &googleChatDecoratedText{
+						TopLabel: mr.Project,
+						Text:     text,
+						WrapText: true,
+					},
+				})
+			}
+			card.Sections = append(card.Sections, chatSection)
+		}
+	}
+
+	if summary.Omitted > 0 {
+		text := html.EscapeString(catalog.T("and_more", summary.Omitted))
+		if summary.MoreURL != "" {
+			text = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(summary.MoreURL), text)
+		}
+		card.Sections = append(card.Sections, googleChatSection{
+			Widgets: []googleChatWidget{{TextParagraph: &googleChatTextParagraph{Text: text}}},
+		})
+	}
+
+	return &googleChatMessage{
+		CardsV2: []googleChatCardWithID{{CardID: "merge-requests", Card: card}},
+	}
+}
+
+// splitBlocks packs consecutive blocks into chunks whose total size, counting
+// a one unit separator between blocks, does not exceed limit. It returns the
+// [start, end) ranges of the chunks. A block larger than limit forms a chunk on
+// its own, so callers shorten such blocks beforehand.
+func splitBlocks(sizes []int, limit int) [][2]int {
+	var chunks [][2]int
+
+	start, size := 0, 0
+	for i, blockSize := range sizes {
+		if i > start && size+1+blockSize > limit {
+			chunks = append(chunks, [2]int{start, i})
+			start, size = i, 0
+		}
+		if i > start {
+			size++
+		}
+		size += blockSize
+	}
+	if start < len(sizes) {
+		chunks = append(chunks, [2]int{start, len(sizes)})
+	}
+
+	return chunks
+}
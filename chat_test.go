@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startWebhookStub records bodies of requests and responds with status.
+func startWebhookStub(t *testing.T, status int) (*httptest.Server, <-chan []byte) {
+	t.Helper()
+
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, bodies
+}
+
+// receiveAll decodes bodies of all requests already received by a webhook stub.
+func receiveAll[T any](t *testing.T, bodies <-chan []byte) []T {
+	t.Helper()
+
+	var msgs []T
+	for {
+		select {
+		case body := <-bodies:
+			var msg T
+			require.NoError(t, json.Unmarshal(body, &msg))
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestChatDestinations(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	mrs := testMergeRequestsForExport()
+
+	t.Run("discord", func(t *testing.T) {
+		server, bodies := startWebhookStub(t, http.StatusNoContent)
+		config := &Config{Discord: ConfigDiscord{WebhookURL: server.URL, Username: "Reminder"}}
+
+		require.NoError(t, newDiscordDestination(config).Send(context.Background(), mrs, now))
+
+		var msg discordMessage
+		require.NoError(t, json.Unmarshal(<-bodies, &msg))
+		assert.Equal(t, "Reminder", msg.Username)
+		require.Len(t, msg.Embeds, 2)
+		assert.Equal(t, "Needs review (1)", msg.Embeds[0].Title)
+		assert.Equal(t, 0x1f75cb, msg.Embeds[0].Color)
+		assert.Contains(t, msg.Embeds[0].Description, `- [!12 Add "export", finally](https://gitlab.com/group/alpha/-/merge_requests/12)`)
+		assert.Contains(t, msg.Embeds[0].Description, "Approved by: Jane Doe")
+		assert.Equal(t, "Waiting on author (1)", msg.Embeds[1].Title)
+	})
+
+	t.Run("mattermost", func(t *testing.T) {
+		server, bodies := startWebhookStub(t, http.StatusOK)
+		config := &Config{
+			Mattermost: ConfigMattermost{WebhookURL: server.URL, Channel: "town-square", Language: "pl"},
+			GroupBy:    GroupByProject,
+		}
+
+		require.NoError(t, newMattermostDestination(config).Send(context.Background(), mrs, now))
+
+		var msg mattermostMessage
+		require.NoError(t, json.Unmarshal(<-bodies, &msg))
+		assert.Equal(t, "town-square", msg.Channel)
+		require.Len(t, msg.Attachments, 2)
+		assert.Equal(t, "Do przeglądu (1)", msg.Attachments[0].Title)
+		assert.Equal(t, "#1f75cb", msg.Attachments[0].Color)
+		assert.Contains(t, msg.Attachments[0].Text, "**group/alpha**\n- [!12")
+	})
+
+	t.Run("discord splits long summary", func(t *testing.T) {
+		server, bodies := startWebhookStub(t, http.StatusNoContent)
+		config := &Config{Discord: ConfigDiscord{WebhookURL: server.URL}, Priority: ConfigPriority{Top: 60}}
+
+		require.NoError(t, newDiscordDestination(config).Send(context.Background(), manyMergeRequests(61), now))
+
+		msgs := receiveAll[discordMessage](t, bodies)
+		require.Greater(t, len(msgs), 1)
+		assert.Equal(t, "...and 1 more", msgs[0].Content)
+
+		var items int
+		for _, msg := range msgs {
+			assert.LessOrEqual(t, len(msg.Embeds), discordMaxEmbeds)
+			var size int
+			for _, embed := range msg.Embeds {
+				assert.Equal(t, "Needs review (60)", embed.Title)
+				assert.LessOrEqual(t, len([]rune(embed.Description)), discordMaxDescriptionRunes)
+				size += len([]rune(embed.Title)) + len([]rune(embed.Description))
+				items += strings.Count(embed.Description, "- [!")
+			}
+			assert.LessOrEqual(t, size, discordMaxEmbedsRunes)
+		}
+		assert.Equal(t, 60, items)
+	})
+
+	t.Run("mattermost splits long summary", func(t *testing.T) {
+		server, bodies := startWebhookStub(t, http.StatusOK)
+		config := &Config{Mattermost: ConfigMattermost{WebhookURL: server.URL}}
+
+		require.NoError(t, newMattermostDestination(config).Send(context.Background(), manyMergeRequests(100), now))
+
+		msgs := receiveAll[mattermostMessage](t, bodies)
+		require.Greater(t, len(msgs), 1)
+
+		var items int
+		for _, msg := range msgs {
+			var size int
+			for _, attachment := range msg.Attachments {
+				size += len([]rune(attachment.Title)) + len([]rune(attachment.Text))
+				items += strings.Count(attachment.Text, "- [!")
+			}
+			assert.LessOrEqual(t, size, mattermostMaxMessageRunes)
+		}
+		assert.Equal(t, 100, items)
+	})
+
+	t.Run("google chat", func(t *testing.T) {
+		server, bodies := startWebhookStub(t, http.StatusOK)
+		config := &Config{GoogleChat: ConfigGoogleChat{WebhookURL: server.URL}, Priority: ConfigPriority{Top: 1, MoreURL: "https://gitlab.com/mrs"}}
+
+		require.NoError(t, newGoogleChatDestination(config).Send(context.Background(), mrs, now))
+
+		var msg googleChatMessage
+		require.NoError(t, json.Unmarshal(<-bodies, &msg))
+		require.Len(t, msg.CardsV2, 1)
+
+		card := msg.CardsV2[0].Card
+		assert.Equal(t, "Open merge requests", card.Header.Title)
+		require.Len(t, card.Sections, 2)
+		assert.Equal(t, "Needs review (1)", card.Sections[0].Header)
+		assert.Equal(t, "group/alpha", card.Sections[0].Widgets[0].DecoratedText.TopLabel)
+		assert.Contains(t, card.Sections[0].Widgets[0].DecoratedText.Text,
+			`<a href="https://gitlab.com/group/alpha/-/merge_requests/12">!12 Add &#34;export&#34;, finally</a><br>Author: John Doe`)
+		assert.Equal(t, `<a href="https://gitlab.com/mrs">...and 1 more</a>`, card.Sections[1].Widgets[0].TextParagraph.Text)
+	})
+
+	t.Run("skips empty list", func(t *testing.T) {
+		config := &Config{Discord: ConfigDiscord{WebhookURL: "http://127.0.0.1:0"}}
+		assert.NoError(t, newDiscordDestination(config).Send(context.Background(), nil, now))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server, _ := startWebhookStub(t, http.StatusBadRequest)
+		config := &Config{Mattermost: ConfigMattermost{WebhookURL: server.URL}}
+
+		err := newMattermostDestination(config).Send(context.Background(), mrs, now)
+		assert.ErrorContains(t, err, "error posting to Mattermost webhook: unexpected status 400 Bad Request")
+	})
+}
+
+func TestEscapeChatMarkdown(t *testing.T) {
+	assert.Equal(t, `fix \*bold\* \_it\_ \[link\] \`+"`code\\`", escapeChatMarkdown("fix *bold* _it_ [link] `code`"))
+}
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "zażółć", truncateRunes("zażółć", 6))
+	assert.Equal(t, "zaż…", truncateRunes("zażółć", 4))
+}
+
+func TestFitTitle(t *testing.T) {
+	render := func(title string) string { return "[" + escapeChatMarkdown(title) + "]" }
+
+	assert.Equal(t, "[short]", fitTitle("short", 10, runeCount, render))
+	assert.Equal(t, "[long…]", fitTitle("long title", 7, runeCount, render))
+	assert.Equal(t, "[…]", fitTitle("long title", 1, runeCount, render))
+}
+
+func TestSplitBlocks(t *testing.T) {
+	assert.Nil(t, splitBlocks(nil, 10))
+	assert.Equal(t, [][2]int{{0, 3}}, splitBlocks([]int{3, 3, 2}, 10))
+	assert.Equal(t, [][2]int{{0, 2}, {2, 3}}, splitBlocks([]int{3, 3, 3}, 10))
+	assert.Equal(t, [][2]int{{0, 1}, {1, 2}, {2, 3}}, splitBlocks([]int{3, 20, 3}, 10))
+}
@@ -0,0 +1,94 @@
+package main
+
+// MergeRequestState describes whose court the ball is in for a merge request.
+type MergeRequestState int
+
+const (
+	StateNeedsReview MergeRequestState = iota
+	StateWaitingOnAuthor
+	StateReadyToMerge
+)
+
+// mergeRequestStates lists the states in the order they are rendered.
+var mergeRequestStates = []MergeRequestState{
+	StateNeedsReview,
+	StateWaitingOnAuthor,
+	StateReadyToMerge,
+}
+
+// MessageKey returns the catalog key of the state title.
+func (s MergeRequestState) MessageKey() string {
+	switch s {
+	case StateWaitingOnAuthor:
+		return "waiting_on_author"
+	case StateReadyToMerge:
+		return "ready_to_merge"
+	default:
+		return "needs_review"
+	}
+}
+
+// Detailed merge statuses reported by GitLab that require action from the author.
+var authorMergeStatuses = map[string]bool{
+	"conflict":                 true,
+	"need_rebase":              true,
+	"discussions_not_resolved": true,
+	"draft_status":             true,
+	"broken_status":            true,
+}
+
+func classifyMergeRequest(mr *MergeRequestWithApprovals) MergeRequestState {
+	if isWaitingOnAuthor(mr) {
+		return StateWaitingOnAuthor
+	}
+
+	if mr.ApprovalsLeft <= 0 && len(mr.ApprovedBy) > 0 {
+		return StateReadyToMerge
+	}
+
+	return StateNeedsReview
+}
+
+func isWaitingOnAuthor(mr *MergeRequestWithApprovals) bool {
+	m := mr.MergeRequest
+
+	if !m.BlockingDiscussionsResolved || m.HasConflicts {
+		return true
+	}
+
+	if authorMergeStatuses[m.DetailedMergeStatus] {
+		return true
+	}
+
+	if pipelineStatus(mr) == "failed" {
+		// A failed pipeline is only the author's problem if nothing happened since
+		// it finished, otherwise a new pipeline is probably about to be started.
+		p := m.HeadPipeline
+		if p == nil || p.FinishedAt == nil || m.UpdatedAt == nil || !m.UpdatedAt.After(*p.FinishedAt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pipelineStatus(mr *MergeRequestWithApprovals) string {
+	if mr.MergeRequest.HeadPipeline != nil {
+		return mr.MergeRequest.HeadPipeline.Status
+	}
+	if mr.MergeRequest.Pipeline != nil {
+		return mr.MergeRequest.Pipeline.Status
+	}
+	return ""
+}
+
+// classifyMergeRequests splits merge requests into buckets keyed by state,
+// preserving the original order within each bucket.
+func classifyMergeRequests(mrs []*MergeRequestWithApprovals) map[MergeRequestState][]*MergeRequestWithApprovals {
+	buckets := make(map[MergeRequestState][]*MergeRequestWithApprovals)
+	for _, mr := range mrs {
+		state := classifyMergeRequest(mr)
+		buckets[state] = append(buckets[state], mr)
+	}
+	return buckets
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestClassifyMergeRequest(t *testing.T) {
+	finishedAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	before := finishedAt.Add(-time.Hour)
+	after := finishedAt.Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		mr       *MergeRequestWithApprovals
+		expected MergeRequestState
+	}{
+		{
+			name: "no approvals",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest:  &gitlab.MergeRequest{BlockingDiscussionsResolved: true},
+				ApprovalsLeft: 1,
+			},
+			expected: StateNeedsReview,
+		},
+		{
+			name: "partially approved",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest:  &gitlab.MergeRequest{BlockingDiscussionsResolved: true},
+				ApprovedBy:    []string{"John Doe"},
+				ApprovalsLeft: 1,
+			},
+			expected: StateNeedsReview,
+		},
+		{
+			name: "fully approved",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest: &gitlab.MergeRequest{BlockingDiscussionsResolved: true},
+				ApprovedBy:   []string{"John Doe"},
+			},
+			expected: StateReadyToMerge,
+		},
+		{
+			name: "unresolved discussions",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest: &gitlab.MergeRequest{BlockingDiscussionsResolved: false},
+				ApprovedBy:   []string{"John Doe"},
+			},
+			expected: StateWaitingOnAuthor,
+		},
+		{
+			name: "conflicts",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest: &gitlab.MergeRequest{BlockingDiscussionsResolved: true, DetailedMergeStatus: "conflict"},
+			},
+			expected: StateWaitingOnAuthor,
+		},
+		{
+			name: "failed pipeline without further activity",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest: &gitlab.MergeRequest{
+					BlockingDiscussionsResolved: true,
+					UpdatedAt:                   &before,
+					HeadPipeline:                &gitlab.Pipeline{Status: "failed", FinishedAt: &finishedAt},
+				},
+				ApprovedBy: []string{"John Doe"},
+			},
+			expected: StateWaitingOnAuthor,
+		},
+		{
+			name: "failed pipeline with activity after it finished",
+			mr: &MergeRequestWithApprovals{
+				MergeRequest: &gitlab.MergeRequest{
+					BlockingDiscussionsResolved: true,
+					UpdatedAt:                   &after,
+					HeadPipeline:                &gitlab.Pipeline{Status: "failed", FinishedAt: &finishedAt},
+				},
+				ApprovalsLeft: 1,
+			},
+			expected: StateNeedsReview,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, classifyMergeRequest(tc.mr))
+		})
+	}
+}
+
+func TestFormatMergeRequestsSummary_Sections(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	author := &gitlab.BasicUser{Name: "John Doe"}
+
+	mrs := []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				Title: "Ready", WebURL: "https://gitlab.com/mr/1", Author: author, CreatedAt: &createdAt,
+				BlockingDiscussionsResolved: true,
+			},
+			ApprovedBy: []string{"Jane Doe"},
+		},
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				Title: "Review me", WebURL: "https://gitlab.com/mr/2", Author: author, CreatedAt: &createdAt,
+				BlockingDiscussionsResolved: true,
+			},
+			ApprovalsLeft: 1,
+		},
+	}
+
+	expected := "*Needs review (1)*\n\n" +
+		":arrow_forward: <https://gitlab.com/mr/2|Review me>\n*Author:* John Doe\n*Created at:* 10 January 2024, 12:00 UTC\n*Approved by:* None\n\n" +
+		"*Ready to merge (1)*\n\n" +
+		":arrow_forward: <https://gitlab.com/mr/1|Ready>\n*Author:* John Doe\n*Created at:* 10 January 2024, 12:00 UTC\n*Approved by:* Jane Doe\n\n"
+
+	summary, err := formatMergeRequestsSummary(mrs, &Config{}, createdAt)
+	require.NoError(t, err)
+	assert.Equal(t, expected, summary)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"text/tabwriter"
+)
+
+const usage = `Usage: mergentle-reminder [command]
+
+Commands:
+  run              Check opened merge requests and send the summary (default).
+  preview          Print the summary to stdout without sending it.
+  validate-config  Load the configuration and check configured projects and groups.
+  list-projects    List projects the configuration expands to.
+  export           Print opened merge requests as JSON or CSV, see export.format.
+  help             Show this help.
+
+Configuration is read from the file at CONFIG_PATH and environment variables.
+If teams are configured, TEAM restricts the command to a single team.
+`
+
+// runCommand dispatches a command given as command line arguments, without the program name.
+func runCommand(ctx context.Context, args []string, env Env, stdout io.Writer) error {
+	command := "run"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	if len(args) > 1 {
+		return fmt.Errorf("unexpected arguments for %s: %v\n\n%s", command, args[1:], usage)
+	}
+
+	switch command {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	case "run", "preview", "validate-config", "list-projects", "export":
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+
+	config, err := loadConfig(env)
+	if err != nil {
+		return fmt.Errorf("error loading configuration: %w", err)
+	}
+
+	if command == "run" {
+		return cmdRun(ctx, config, env)
+	}
+
+	client, err := newGitLabClient(config)
+	if err != nil {
+		return err
+	}
+
+	if command == "export" {
+		if len(config.Teams) > 1 {
+			return fmt.Errorf("export of multiple teams is not supported, select one with the TEAM environment variable")
+		}
+		return cmdExport(ctx, config.teams()[0], client, &SystemClock{}, stdout)
+	}
+
+	return forEachTeam(config, newCachingGitLabClient(client), stdout, func(team *Config, client GitLabClient) error {
+		switch command {
+		case "preview":
+			return cmdPreview(ctx, team, client, &SystemClock{}, stdout)
+		case "validate-config":
+			return cmdValidateConfig(ctx, team, client, stdout)
+		default:
+			return cmdListProjects(ctx, team, client, stdout)
+		}
+	})
+}
+
+// forEachTeam runs a command for every team, printing the team name before its output if teams are used.
+func forEachTeam(config *Config, client GitLabClient, w io.Writer, cmd func(team *Config, client GitLabClient) error) error {
+	for i, team := range config.teams() {
+		if team.team != "" {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "== %s ==\n", team.team)
+		}
+
+		if err := cmd(team, client); err != nil {
+			if team.team != "" {
+				return fmt.Errorf("team %s: %w", team.team, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// cmdRun executes once or according to the cron schedule if it is configured.
+func cmdRun(ctx context.Context, config *Config, env Env) error {
+	if !config.scheduled() {
+		log.Printf("Running in one-shot mode")
+		if err := execute(ctx, config, &SystemClock{}); err != nil {
+			return fmt.Errorf("error executing: %w", err)
+		}
+		return nil
+	}
+
+	return runScheduler(ctx, config, env)
+}
+
+func cmdPreview(ctx context.Context, config *Config, client GitLabClient, clock Clock, w io.Writer) error {
+	now := clock.Now()
+
+	mrs, err := collectMergeRequests(ctx, config, client, now)
+	if err != nil {
+		return err
+	}
+
+	if len(mrs) == 0 {
+		log.Println("No opened merge requests found.")
+		return nil
+	}
+
+	summary, err := formatMergeRequestsSummary(mrs, config, now)
+	if err != nil {
+		return fmt.Errorf("error rendering merge requests summary: %w", err)
+	}
+
+	_, err = fmt.Fprint(w, summary)
+	return err
+}
+
+func cmdValidateConfig(ctx context.Context, config *Config, client GitLabClient, w io.Writer) error {
+	projects, err := resolveProjects(ctx, config, client)
+	if err != nil {
+		return fmt.Errorf("error resolving projects: %w", err)
+	}
+
+	if len(projects) == 0 {
+		return fmt.Errorf("configured groups contain no projects")
+	}
+
+	_, err = fmt.Fprintf(w, "Configuration is valid, %d projects to check.\n", len(projects))
+	return err
+}
+
+func cmdListProjects(ctx context.Context, config *Config, client GitLabClient, w io.Writer) error {
+	projects, err := resolveProjects(ctx, config, client)
+	if err != nil {
+		return fmt.Errorf("error resolving projects: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPATH\tURL")
+	for _, project := range projects {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", project.ID, project.PathWithNamespace, project.WebURL)
+	}
+	return tw.Flush()
+}
+
+// cmdExport writes merge requests to w in the configured export format, JSON by default.
+func cmdExport(ctx context.Context, config *Config, client GitLabClient, clock Clock, w io.Writer) error {
+	now := clock.Now()
+
+	mrs, err := collectMergeRequests(ctx, config, client, now)
+	if err != nil {
+		return err
+	}
+
+	format := config.Export.Format
+	if format == "" {
+		format = ExportFormatJSON
+	}
+
+	return writeExport(w, format, newExport(mrs, now))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexoid/mergentle-reminder/mocks"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestRunCommand(t *testing.T) {
+	env := &MockEnv{values: map[string]string{"CONFIG_PATH": "NONEXISTING.yaml"}}
+
+	t.Run("help", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runCommand(context.Background(), []string{"help"}, env, &out))
+		assert.Contains(t, out.String(), "Usage: mergentle-reminder [command]")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		err := runCommand(context.Background(), []string{"deploy"}, env, &bytes.Buffer{})
+		assert.ErrorContains(t, err, `unknown command "deploy"`)
+	})
+
+	t.Run("unexpected arguments", func(t *testing.T) {
+		err := runCommand(context.Background(), []string{"preview", "now"}, env, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "unexpected arguments for preview: [now]")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		err := runCommand(context.Background(), []string{"validate-config"}, env, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "GITLAB_TOKEN environment variable is required")
+	})
+}
+
+func expectProjects(client *mocks.GitLabClient, projects ...*gitlab.Project) {
+	for _, project := range projects {
+		client.EXPECT().GetProject(mock.Anything, project.ID, (*gitlab.GetProjectOptions)(nil)).Return(project, &gitlab.Response{}, nil).Once()
+	}
+}
+
+func TestCmdListProjects(t *testing.T) {
+	config := &Config{Projects: []ConfigProject{{ID: 1}, {ID: 22}}}
+
+	client := mocks.NewGitLabClient(t)
+	expectProjects(client,
+		&gitlab.Project{ID: 1, PathWithNamespace: "group/alpha", WebURL: "https://gitlab.com/group/alpha"},
+		&gitlab.Project{ID: 22, PathWithNamespace: "group/sub/beta", WebURL: "https://gitlab.com/group/sub/beta"},
+	)
+
+	var out bytes.Buffer
+	require.NoError(t, cmdListProjects(context.Background(), config, client, &out))
+
+	expected := "ID  PATH            URL\n" +
+		"1   group/alpha     https://gitlab.com/group/alpha\n" +
+		"22  group/sub/beta  https://gitlab.com/group/sub/beta\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestCmdValidateConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		config := &Config{Projects: []ConfigProject{{ID: 1}}}
+
+		client := mocks.NewGitLabClient(t)
+		expectProjects(client, &gitlab.Project{ID: 1})
+
+		var out bytes.Buffer
+		require.NoError(t, cmdValidateConfig(context.Background(), config, client, &out))
+		assert.Equal(t, "Configuration is valid, 1 projects to check.\n", out.String())
+	})
+
+	t.Run("inaccessible project", func(t *testing.T) {
+		config := &Config{Projects: []ConfigProject{{ID: 1}}}
+
+		client := mocks.NewGitLabClient(t)
+		client.EXPECT().GetProject(mock.Anything, 1, mock.Anything).Return(nil, nil, assert.AnError)
+
+		err := cmdValidateConfig(context.Background(), config, client, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "error fetching project 1")
+	})
+}
+
+func TestCmdPreview(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	config := &Config{Projects: []ConfigProject{{ID: 1}}}
+
+	client := mocks.NewGitLabClient(t)
+	client.EXPECT().ListProjectMergeRequests(mock.Anything, 1, mock.Anything).Return(
+		[]*gitlab.MergeRequest{{
+			IID: 1, Title: "MR", WebURL: "https://gitlab.com/mr/1", CreatedAt: &createdAt, BlockingDiscussionsResolved: true,
+			Author: &gitlab.BasicUser{Name: "John Doe"},
+		}},
+		&gitlab.Response{CurrentPage: 1, TotalPages: 1},
+		nil,
+	)
+	client.EXPECT().GetMergeRequestApprovalsConfiguration(mock.Anything, 1, 1).Return(
+		&gitlab.MergeRequestApprovals{ApprovalsLeft: 1}, &gitlab.Response{}, nil,
+	)
+
+	var out bytes.Buffer
+	require.NoError(t, cmdPreview(context.Background(), config, client, &fixedClock{now: createdAt}, &out))
+
+	assert.Equal(t, "*Needs review (1)*\n\n"+
+		":arrow_forward: <https://gitlab.com/mr/1|MR>\n*Author:* John Doe\n*Created at:* 10 January 2024, 12:00 UTC\n*Approved by:* None\n\n",
+		out.String())
+}
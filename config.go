@@ -1,26 +1,302 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
-	"gopkg.in/yaml.v2"
+	"github.com/reugn/go-quartz/quartz"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	GitLab struct {
-		URL   string `yaml:"url"`
-		Token string `yaml:"token"`
-	} `yaml:"gitlab"`
-	Slack struct {
-		WebhookURL string `yaml:"webhook_url"`
-	} `yaml:"slack"`
+	GitLab       ConfigGitLab    `yaml:"gitlab"`
+	Slack        ConfigSlack     `yaml:"slack"`
 	Projects     []ConfigProject `yaml:"projects"`
 	Groups       []ConfigGroup   `yaml:"groups"`
 	CronSchedule string          `yaml:"cron_schedule"`
 	Authors      []ConfigAuthor  `yaml:"authors"`
+	SortBy       string          `yaml:"sort_by"`
+	GroupBy      string          `yaml:"group_by"`
+	Priority     ConfigPriority  `yaml:"priority"`
+	Template     string          `yaml:"template"`
+	TemplateFile string          `yaml:"template_file"`
+	Timezone     string          `yaml:"timezone"`
+	// CronTimezone is the timezone cron schedules are evaluated in, UTC if empty.
+	CronTimezone string `yaml:"cron_timezone"`
+	// Schedules run the bot on additional cron schedules.
+	Schedules []ConfigSchedule `yaml:"schedules"`
+	// ShutdownTimeout is how long a run in progress may take to finish once
+	// the bot is asked to stop in cron mode, defaultShutdownTimeout if empty.
+	ShutdownTimeout string `yaml:"shutdown_timeout"`
+	// RunTimeout limits how long fetching and sending may take in a single
+	// run, unlimited if empty.
+	RunTimeout string `yaml:"run_timeout"`
+	// Calendar lists non-working days, on which scheduled runs are skipped.
+	Calendar ConfigCalendar `yaml:"calendar"`
+	// OutOfOffice marks merge requests of absent reviewers and authors.
+	OutOfOffice ConfigOutOfOffice `yaml:"out_of_office"`
+	// RelativeDates renders dates as "4 days ago" instead of absolute timestamps.
+	RelativeDates bool `yaml:"relative_dates"`
+	// BusinessDays counts only working days when rendering relative dates.
+	BusinessDays bool `yaml:"business_days"`
+	// DryRun writes messages to DryRunOutput (stdout if empty) instead of sending them.
+	DryRun       bool              `yaml:"dry_run"`
+	DryRunOutput string            `yaml:"dry_run_output"`
+	Export       ConfigExport      `yaml:"export"`
+	Reports      []ConfigReport    `yaml:"reports"`
+	GitLabIssue  ConfigGitLabIssue `yaml:"gitlab_issue"`
+	GitLabWiki   ConfigGitLabWiki  `yaml:"gitlab_wiki"`
+	Email        ConfigEmail       `yaml:"email"`
+	Discord      ConfigDiscord     `yaml:"discord"`
+	Mattermost   ConfigMattermost  `yaml:"mattermost"`
+	GoogleChat   ConfigGoogleChat  `yaml:"google_chat"`
+	Webhook      ConfigWebhook     `yaml:"webhook"`
+	Telegram     ConfigTelegram    `yaml:"telegram"`
+	Matrix       ConfigMatrix      `yaml:"matrix"`
+	// Teams split the configuration into independent teams sharing a single GitLab connection.
+	Teams []ConfigTeam `yaml:"teams"`
+
+	// team is the name of the team this configuration belongs to, empty if teams are not used.
+	team string
+	// summaryTemplate is parsed from Template or TemplateFile by loadConfig.
+	summaryTemplate *template.Template
+	// webhookTemplate is parsed from Webhook.Template or Webhook.TemplateFile by loadConfig.
+	webhookTemplate *template.Template
+	// location is loaded from Timezone by loadConfig, nil keeps dates as returned by GitLab.
+	location *time.Location
+	// cronLocation is loaded from CronTimezone by loadConfig, nil for UTC.
+	cronLocation *time.Location
+	// calendar is loaded from Calendar by loadConfig, nil if no non-working days are configured.
+	calendar *calendar
+	// shutdownTimeout is parsed from ShutdownTimeout by loadConfig.
+	shutdownTimeout time.Duration
+	// runTimeout is parsed from RunTimeout by loadConfig, 0 if runs are not limited.
+	runTimeout time.Duration
+	// fileContents are contents of referenced files read by loadConfig, used to
+	// find changes on reload. See referencedFiles for the keys.
+	fileContents map[string]string
+}
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// ConfigSchedule is an additional cron schedule, optionally rendering a
+// different template or sending to some of the configured destinations only.
+type ConfigSchedule struct {
+	Cron string `yaml:"cron"`
+	// Timezone overrides CronTimezone for this schedule.
+	Timezone     string `yaml:"timezone"`
+	Template     string `yaml:"template"`
+	TemplateFile string `yaml:"template_file"`
+	// Destinations are names of destination sections, e.g. slack or email.
+	// All configured destinations are used if empty.
+	Destinations []string `yaml:"destinations"`
+
+	// location is loaded from Timezone by loadConfig, nil for the one of the configuration.
+	location *time.Location
+	// summaryTemplate is parsed from Template or TemplateFile by loadConfig.
+	summaryTemplate *template.Template
+}
+
+// ConfigCalendar configures non-working days. Days from all sources are combined.
+type ConfigCalendar struct {
+	// ICSFile is an iCalendar file, e.g. exported public holidays or team offsites.
+	ICSFile string `yaml:"ics_file"`
+	// HolidaysFile is a YAML file with lists of holidays keyed by country or team.
+	HolidaysFile string `yaml:"holidays_file"`
+	// Country selects the list of holidays from HolidaysFile.
+	Country  string          `yaml:"country"`
+	Holidays []ConfigHoliday `yaml:"holidays"`
+}
+
+// ConfigOutOfOffice configures how absent users are found. Absences from the
+// file and GitLab statuses are combined.
+type ConfigOutOfOffice struct {
+	// GitLabStatus reads user statuses from GitLab. Users are away if their
+	// availability is busy or their status emoji is one of Emojis.
+	GitLabStatus bool     `yaml:"gitlab_status"`
+	Emojis       []string `yaml:"emojis"`
+	// File is a YAML list of absences with username and optional from and until dates.
+	File string `yaml:"file"`
+	// SuggestReviewer suggests another reviewer of the same group for merge requests of away reviewers.
+	SuggestReviewer bool `yaml:"suggest_reviewer"`
+}
+
+// ConfigHoliday is a non-working date, written either as a plain YYYY-MM-DD
+// date or as a mapping with the date and a name.
+type ConfigHoliday struct {
+	Date string `yaml:"date"`
+	Name string `yaml:"name"`
+}
+
+func (h *ConfigHoliday) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		h.Date = value.Value
+		return nil
+	}
+
+	type plain ConfigHoliday
+	return value.Decode((*plain)(h))
+}
+
+// ConfigTeam has the same settings as the top level of the configuration,
+// except for the GitLab connection and teams.
+type ConfigTeam struct {
+	Name   string `yaml:"name"`
+	Config `yaml:",inline"`
+}
+
+type ConfigGitLab struct {
+	URL       string `yaml:"url"`
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+}
+
+type ConfigSlack struct {
+	WebhookURL     string `yaml:"webhook_url"`
+	WebhookURLFile string `yaml:"webhook_url_file"`
+	Language       string `yaml:"language"`
+}
+
+type ConfigPriority struct {
+	Weights ConfigPriorityWeights `yaml:"weights"`
+	Top     int                   `yaml:"top"`
+	MoreURL string                `yaml:"more_url"`
+}
+
+type ConfigPriorityWeights struct {
+	Age                  float64            `yaml:"age"`
+	Size                 float64            `yaml:"size"`
+	ApprovalsMissing     float64            `yaml:"approvals_missing"`
+	MilestoneDue         float64            `yaml:"milestone_due"`
+	MilestoneHorizonDays int                `yaml:"milestone_horizon_days"`
+	Labels               map[string]float64 `yaml:"labels"`
+}
+
+// ConfigExport enables writing collected merge requests as JSON or CSV on every run.
+type ConfigExport struct {
+	Format string `yaml:"format"`
+	// Path of the file to write to, stdout if empty.
+	Path string `yaml:"path"`
+}
+
+// ConfigReport is a Markdown or HTML report written to Path on every run.
+type ConfigReport struct {
+	Format   string `yaml:"format"`
+	Path     string `yaml:"path"`
+	Language string `yaml:"language"`
+}
+
+// ConfigGitLabIssue configures an issue kept up to date with the list of merge requests.
+type ConfigGitLabIssue struct {
+	ProjectID int `yaml:"project_id"`
+	// IssueIID selects the issue to update, otherwise it is found by Label or created.
+	IssueIID int    `yaml:"issue_iid"`
+	Label    string `yaml:"label"`
+	Title    string `yaml:"title"`
+	Language string `yaml:"language"`
+}
+
+// ConfigGitLabWiki configures a wiki page kept up to date with the list of merge requests.
+type ConfigGitLabWiki struct {
+	ProjectID int    `yaml:"project_id"`
+	Title     string `yaml:"title"`
+	// Slug selects the page to update, otherwise it is found by Title or created.
+	Slug     string `yaml:"slug"`
+	Language string `yaml:"language"`
+}
+
+// ConfigEmail configures an SMTP server and recipients of the email digest.
+type ConfigEmail struct {
+	Host         string `yaml:"host"`
+	Port         int    `yaml:"port"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"password_file"`
+	// TLS is one of starttls (default), tls for implicit TLS, or none.
+	TLS        string                 `yaml:"tls"`
+	From       string                 `yaml:"from"`
+	Subject    string                 `yaml:"subject"`
+	Language   string                 `yaml:"language"`
+	Recipients []ConfigEmailRecipient `yaml:"recipients"`
+}
+
+// ConfigEmailRecipient receives merge requests from the listed projects and
+// groups, or all merge requests if none are listed.
+type ConfigEmailRecipient struct {
+	Address  string `yaml:"address"`
+	Projects []int  `yaml:"projects"`
+	// Groups are full paths of groups, e.g. "my-org/backend", including their subgroups.
+	Groups []string `yaml:"groups"`
+}
+
+// ConfigDiscord configures a Discord incoming webhook.
+type ConfigDiscord struct {
+	WebhookURL     string `yaml:"webhook_url"`
+	WebhookURLFile string `yaml:"webhook_url_file"`
+	// Username overrides the default name of the webhook.
+	Username string `yaml:"username"`
+	Language string `yaml:"language"`
+}
+
+// ConfigMattermost configures a Mattermost incoming webhook.
+type ConfigMattermost struct {
+	WebhookURL     string `yaml:"webhook_url"`
+	WebhookURLFile string `yaml:"webhook_url_file"`
+	// Channel and Username override the defaults of the webhook, if it allows that.
+	Channel  string `yaml:"channel"`
+	Username string `yaml:"username"`
+	Language string `yaml:"language"`
+}
+
+// ConfigGoogleChat configures a Google Chat space incoming webhook.
+type ConfigGoogleChat struct {
+	WebhookURL     string `yaml:"webhook_url"`
+	WebhookURLFile string `yaml:"webhook_url_file"`
+	Language       string `yaml:"language"`
+}
+
+// ConfigWebhook configures a generic HTTP endpoint receiving a JSON document on every run.
+type ConfigWebhook struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	// Template and TemplateFile replace the JSON document with a custom body.
+	Template     string `yaml:"template"`
+	TemplateFile string `yaml:"template_file"`
+	// Secret enables HMAC-SHA256 signing of the body.
+	Secret     string `yaml:"secret"`
+	SecretFile string `yaml:"secret_file"`
+	// Retries is the number of retries on network errors and 5xx responses, 3 if not set.
+	Retries *int `yaml:"retries"`
+}
+
+// ConfigTelegram configures a Telegram bot posting to a chat.
+type ConfigTelegram struct {
+	BotToken     string `yaml:"bot_token"`
+	BotTokenFile string `yaml:"bot_token_file"`
+	// ChatID is a numeric chat ID or @username of a channel.
+	ChatID string `yaml:"chat_id"`
+	// APIURL overrides the Bot API server, e.g. a self-hosted one.
+	APIURL   string `yaml:"api_url"`
+	Language string `yaml:"language"`
+}
+
+// ConfigMatrix configures a Matrix user posting to a room.
+type ConfigMatrix struct {
+	HomeserverURL   string `yaml:"homeserver_url"`
+	AccessToken     string `yaml:"access_token"`
+	AccessTokenFile string `yaml:"access_token_file"`
+	RoomID          string `yaml:"room_id"`
+	Language        string `yaml:"language"`
 }
 
 type ConfigGroup struct {
@@ -46,33 +322,49 @@ func (e *OsEnv) Getenv(key string) string {
 	return os.Getenv(key)
 }
 
-func loadConfig(env Env) (*Config, error) {
-	configPath := env.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = "config.yaml"
+// configPath returns the path of the configuration file, config.yaml unless set by CONFIG_PATH.
+func configPath(env Env) string {
+	if path := env.Getenv("CONFIG_PATH"); path != "" {
+		return path
 	}
+	return "config.yaml"
+}
+
+func loadConfig(env Env) (*Config, error) {
+	configPath := configPath(env)
 
 	config := &Config{}
-	var err error
+	v := &configValidator{}
 
 	if _, err := os.Stat(configPath); err == nil {
-		config, err = readConfig(configPath)
+		format, err := configFormat(configPath, env)
 		if err != nil {
+			return nil, err
+		}
+
+		config, v.root, err = readConfig(configPath, format, env)
+		if !errors.As(err, &v.errs) && err != nil {
 			return nil, fmt.Errorf("error reading configuration file: %v", err)
 		}
+		v.resolveSecretFiles(config)
+		for i := range config.Teams {
+			v.resolveSecretFiles(&config.Teams[i].Config)
+		}
 	}
 
+	var err error
+
 	if env := env.Getenv("PROJECTS"); env != "" {
 		config.Projects, err = parseIDsAsConfigProjects(env)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing PROJECTS environment variable: %v", err)
+			v.errorf("", "error parsing PROJECTS environment variable: %v", err)
 		}
 	}
 
 	if env := env.Getenv("GROUPS"); env != "" {
 		config.Groups, err = parseIDsAsConfigGroups(env)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing GROUPS environment variable: %v", err)
+			v.errorf("", "error parsing GROUPS environment variable: %v", err)
 		}
 	}
 
@@ -83,27 +375,68 @@ func loadConfig(env Env) (*Config, error) {
 	if config.GitLab.URL == "" {
 		config.GitLab.URL = "https://gitlab.com"
 	}
+	v.checkURL("gitlab.url", config.GitLab.URL)
 
-	gitlabToken := env.Getenv("GITLAB_TOKEN")
+	gitlabToken := v.getenvSecret(env, "GITLAB_TOKEN")
 	if gitlabToken != "" {
 		config.GitLab.Token = gitlabToken
 	}
 	if config.GitLab.Token == "" {
-		return nil, fmt.Errorf("GITLAB_TOKEN environment variable is required")
+		v.errorf("", "GITLAB_TOKEN environment variable is required")
+	}
+
+	if dryRun := env.Getenv("DRY_RUN"); dryRun != "" {
+		config.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			v.errorf("", "error parsing DRY_RUN environment variable: %v", err)
+		}
 	}
 
-	slackWebhookURL := env.Getenv("SLACK_WEBHOOK_URL")
+	if dryRunOutput := env.Getenv("DRY_RUN_OUTPUT"); dryRunOutput != "" {
+		config.DryRunOutput = dryRunOutput
+	}
+
+	slackWebhookURL := v.getenvSecret(env, "SLACK_WEBHOOK_URL")
 	if slackWebhookURL != "" {
 		config.Slack.WebhookURL = slackWebhookURL
 	}
-	if config.Slack.WebhookURL == "" {
-		return nil, fmt.Errorf("SLACK_WEBHOOK_URL environment variable is required")
+
+	if smtpPassword := v.getenvSecret(env, "SMTP_PASSWORD"); smtpPassword != "" {
+		config.Email.Password = smtpPassword
+	}
+
+	if discordWebhookURL := v.getenvSecret(env, "DISCORD_WEBHOOK_URL"); discordWebhookURL != "" {
+		config.Discord.WebhookURL = discordWebhookURL
+	}
+
+	if mattermostWebhookURL := v.getenvSecret(env, "MATTERMOST_WEBHOOK_URL"); mattermostWebhookURL != "" {
+		config.Mattermost.WebhookURL = mattermostWebhookURL
+	}
+
+	if googleChatWebhookURL := v.getenvSecret(env, "GOOGLE_CHAT_WEBHOOK_URL"); googleChatWebhookURL != "" {
+		config.GoogleChat.WebhookURL = googleChatWebhookURL
+	}
+
+	if webhookURL := env.Getenv("WEBHOOK_URL"); webhookURL != "" {
+		config.Webhook.URL = webhookURL
+	}
+
+	if webhookSecret := v.getenvSecret(env, "WEBHOOK_SECRET"); webhookSecret != "" {
+		config.Webhook.Secret = webhookSecret
+	}
+
+	if telegramBotToken := v.getenvSecret(env, "TELEGRAM_BOT_TOKEN"); telegramBotToken != "" {
+		config.Telegram.BotToken = telegramBotToken
+	}
+
+	if matrixAccessToken := v.getenvSecret(env, "MATRIX_ACCESS_TOKEN"); matrixAccessToken != "" {
+		config.Matrix.AccessToken = matrixAccessToken
 	}
 
 	if env := env.Getenv("AUTHORS"); env != "" {
 		config.Authors, err = parseAuthors(env)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing AUTHORS environment variable: %v", err)
+			v.errorf("", "error parsing AUTHORS environment variable: %v", err)
 		}
 	}
 
@@ -112,26 +445,652 @@ func loadConfig(env Env) (*Config, error) {
 		config.CronSchedule = cronSchedule
 	}
 
-	if len(config.Projects) == 0 && len(config.Groups) == 0 {
-		return nil, fmt.Errorf("neither groups nor projects were provided")
+	if sortBy := env.Getenv("SORT_BY"); sortBy != "" {
+		config.SortBy = sortBy
+	}
+
+	if groupBy := env.Getenv("GROUP_BY"); groupBy != "" {
+		config.GroupBy = groupBy
+	}
+
+	if exportFormat := env.Getenv("EXPORT_FORMAT"); exportFormat != "" {
+		config.Export.Format = exportFormat
+	}
+
+	if exportPath := env.Getenv("EXPORT_PATH"); exportPath != "" {
+		config.Export.Path = exportPath
+	}
+
+	if timezone := env.Getenv("TIMEZONE"); timezone != "" {
+		config.Timezone = timezone
+	}
+
+	if cronTimezone := env.Getenv("CRON_TIMEZONE"); cronTimezone != "" {
+		config.CronTimezone = cronTimezone
+	}
+
+	if shutdownTimeout := env.Getenv("SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		config.ShutdownTimeout = shutdownTimeout
+	}
+
+	if runTimeout := env.Getenv("RUN_TIMEOUT"); runTimeout != "" {
+		config.RunTimeout = runTimeout
 	}
 
+	config.shutdownTimeout = defaultShutdownTimeout
+	if config.ShutdownTimeout != "" {
+		config.shutdownTimeout = v.parseTimeout("shutdown_timeout", config.ShutdownTimeout)
+	}
+	if config.RunTimeout != "" {
+		config.runTimeout = v.parseTimeout("run_timeout", config.RunTimeout)
+	}
+
+	if len(config.Teams) == 0 {
+		v.validateConfig(config)
+	} else {
+		v.validateTeams(config)
+	}
+
+	if team := env.Getenv("TEAM"); team != "" && len(v.errs) == 0 {
+		if err := config.selectTeam(team); err != nil {
+			v.errorf("", "%v", err)
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return nil, v.errs
+	}
+
+	config.fileContents = config.readReferencedFiles()
 	return config, nil
 }
 
-func readConfig(file string) (*Config, error) {
+// validateConfig validates settings of a single team, which is the whole
+// configuration if teams are not used, loading its templates and timezone.
+func (v *configValidator) validateConfig(config *Config) {
+	var err error
+
+	if config.Slack.WebhookURL == "" && !config.DryRun && !config.hasOtherDestinations() {
+		v.errorf("", "SLACK_WEBHOOK_URL environment variable is required unless another destination is configured")
+	}
+
+	if config.CronSchedule != "" {
+		if _, err := quartz.NewCronTrigger(config.CronSchedule); err != nil {
+			v.errorf("cron_schedule", "invalid cron_schedule %q: %v", config.CronSchedule, err)
+		}
+	}
+
+	if config.CronTimezone != "" {
+		config.cronLocation, err = time.LoadLocation(config.CronTimezone)
+		if err != nil {
+			v.errorf("cron_timezone", "error loading cron_timezone: %v", err)
+		}
+	}
+
+	for i := range config.Schedules {
+		v.validateSchedule(config, i)
+	}
+
+	config.calendar, err = loadCalendar(config.Calendar, config.cronLocation)
+	if err != nil {
+		v.errorf("calendar", "error in calendar configuration: %v", err)
+	}
+
+	if config.OutOfOffice.File != "" {
+		if _, err := readAbsences(config.OutOfOffice.File); err != nil {
+			v.errorf("out_of_office.file", "error in out_of_office configuration: %v", err)
+		}
+	}
+
+	v.check("sort_by", validateSortAndGroup(config.SortBy, ""))
+	v.check("group_by", validateSortAndGroup("", config.GroupBy))
+
+	if f := config.Export.Format; f != "" && f != ExportFormatJSON && f != ExportFormatCSV {
+		v.errorf("export.format", "invalid export format %q, must be one of: %s, %s", f, ExportFormatJSON, ExportFormatCSV)
+	}
+
+	for i, report := range config.Reports {
+		if err := validateReport(report); err != nil {
+			v.errorf(fmt.Sprintf("reports.%d", i), "error in reports configuration: %v", err)
+		}
+	}
+
+	if config.Timezone != "" {
+		config.location, err = time.LoadLocation(config.Timezone)
+		if err != nil {
+			v.errorf("timezone", "error loading timezone: %v", err)
+		}
+	}
+
+	v.validateIDs(config)
+
+	if config.Priority.Top < 0 {
+		v.errorf("priority.top", "priority.top must not be negative")
+	}
+	if config.Priority.Weights.MilestoneHorizonDays < 0 {
+		v.errorf("priority.weights.milestone_horizon_days", "priority.weights.milestone_horizon_days must not be negative")
+	}
+	v.checkURL("priority.more_url", config.Priority.MoreURL)
+
+	v.checkURL("slack.webhook_url", config.Slack.WebhookURL)
+	v.checkURL("discord.webhook_url", config.Discord.WebhookURL)
+	v.checkURL("mattermost.webhook_url", config.Mattermost.WebhookURL)
+	v.checkURL("google_chat.webhook_url", config.GoogleChat.WebhookURL)
+	v.checkURL("webhook.url", config.Webhook.URL)
+	v.checkURL("telegram.api_url", config.Telegram.APIURL)
+	v.checkURL("matrix.homeserver_url", config.Matrix.HomeserverURL)
+
+	languages := []struct {
+		section  string
+		language string
+	}{
+		{"slack", config.Slack.Language},
+		{"gitlab_issue", config.GitLabIssue.Language},
+		{"gitlab_wiki", config.GitLabWiki.Language},
+		{"discord", config.Discord.Language},
+		{"mattermost", config.Mattermost.Language},
+		{"google_chat", config.GoogleChat.Language},
+		{"telegram", config.Telegram.Language},
+		{"matrix", config.Matrix.Language},
+	}
+	for _, l := range languages {
+		if _, err := catalogFor(l.language); err != nil {
+			v.errorf(l.section+".language", "error in %s configuration: %v", l.section, err)
+		}
+	}
+
+	if config.Telegram.ChatID != "" && config.Telegram.BotToken == "" {
+		v.errorf("telegram", "error in telegram configuration: bot_token is required")
+	}
+
+	if config.Matrix.RoomID != "" && (config.Matrix.HomeserverURL == "" || config.Matrix.AccessToken == "") {
+		v.errorf("matrix", "error in matrix configuration: homeserver_url and access_token are required")
+	}
+
+	if config.Email.Host != "" {
+		if err := validateEmail(config.Email); err != nil {
+			v.errorf("email", "error in email configuration: %v", err)
+		}
+	}
+
+	config.summaryTemplate, err = loadSummaryTemplate(config)
+	if err != nil {
+		v.errorf("template", "error loading template: %v", err)
+	}
+
+	if config.Webhook.Retries != nil && *config.Webhook.Retries < 0 {
+		v.errorf("webhook.retries", "error in webhook configuration: retries must not be negative")
+	}
+
+	config.webhookTemplate, err = loadWebhookTemplate(config.Webhook)
+	if err != nil {
+		v.errorf("webhook.template", "error loading webhook template: %v", err)
+	}
+
+	if len(config.Projects) == 0 && len(config.Groups) == 0 {
+		v.errorf("", "neither groups nor projects were provided")
+	}
+}
+
+// validateSchedule validates an additional schedule, loading its template and timezone.
+func (v *configValidator) validateSchedule(config *Config, i int) {
+	schedule := &config.Schedules[i]
+	path := fmt.Sprintf("schedules.%d", i)
+
+	if schedule.Cron == "" {
+		v.errorf(path, "schedules[%d].cron is required", i)
+	} else if _, err := quartz.NewCronTrigger(schedule.Cron); err != nil {
+		v.errorf(path+".cron", "invalid schedules[%d].cron %q: %v", i, schedule.Cron, err)
+	}
+
+	schedule.location = config.cronLocation
+	if schedule.Timezone != "" {
+		location, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			v.errorf(path+".timezone", "error loading schedules[%d].timezone: %v", i, err)
+		}
+		schedule.location = location
+	}
+
+	var err error
+	schedule.summaryTemplate, err = loadSummaryTemplate(&Config{Template: schedule.Template, TemplateFile: schedule.TemplateFile})
+	if err != nil {
+		v.errorf(path+".template", "error loading schedules[%d] template: %v", i, err)
+	}
+
+	for j, name := range schedule.Destinations {
+		switch {
+		case !slices.Contains(destinationSections, name):
+			v.errorf(fmt.Sprintf("%s.destinations.%d", path, j), "invalid schedules[%d].destinations[%d] %q, must be one of: %s",
+				i, j, name, strings.Join(destinationSections, ", "))
+		case !config.hasDestination(name):
+			v.errorf(fmt.Sprintf("%s.destinations.%d", path, j), "schedules[%d]: destination %q is not configured", i, name)
+		}
+	}
+}
+
+// validateTeams validates a configuration with teams. Teams inherit the
+// GitLab connection, dry run mode, schedule, calendar, out-of-office settings
+// and timezone from the top level, where projects, groups, destinations and
+// settings of the summary are not allowed.
+func (v *configValidator) validateTeams(config *Config) {
+	if len(config.Projects) > 0 || len(config.Groups) > 0 {
+		v.errorf("", "projects and groups must be configured per team when teams are used")
+	}
+	if config.Slack.WebhookURL != "" || config.hasOtherDestinations() {
+		v.errorf("", "destinations must be configured per team when teams are used")
+	}
+
+	// Settings neither inherited by teams nor used at the top level would be silently ignored.
+	perTeam := []struct {
+		path  string
+		value any
+	}{
+		{"authors", config.Authors},
+		{"sort_by", config.SortBy},
+		{"group_by", config.GroupBy},
+		{"priority", config.Priority},
+		{"template", config.Template},
+		{"template_file", config.TemplateFile},
+		{"relative_dates", config.RelativeDates},
+		{"business_days", config.BusinessDays},
+		{"export", config.Export},
+		{"reports", config.Reports},
+	}
+	for _, setting := range perTeam {
+		if !reflect.ValueOf(setting.value).IsZero() {
+			v.errorf(setting.path, "%s must be configured per team when teams are used", setting.path)
+		}
+	}
+
+	names := make(map[string]bool)
+	scheduled := config.CronSchedule != "" || len(config.Schedules) > 0
+
+	for i := range config.Teams {
+		team := &config.Teams[i]
+		path := fmt.Sprintf("teams.%d", i)
+
+		switch {
+		case team.Name == "":
+			v.errorf(path, "teams[%d].name is required", i)
+		case names[team.Name]:
+			v.errorf(path+".name", "duplicate team name %q", team.Name)
+		}
+		names[team.Name] = true
+
+		if team.GitLab != (ConfigGitLab{}) || len(team.Teams) > 0 {
+			v.errorf(path, "team %q: gitlab and teams can only be configured at the top level", team.Name)
+		}
+		if team.ShutdownTimeout != "" {
+			v.errorf(path+".shutdown_timeout", "team %q: shutdown_timeout can only be configured at the top level", team.Name)
+		}
+		if team.RunTimeout != "" {
+			v.errorf(path+".run_timeout", "team %q: run_timeout can only be configured at the top level", team.Name)
+		}
+
+		team.GitLab = config.GitLab
+		team.DryRun = team.DryRun || config.DryRun
+		if team.DryRunOutput == "" {
+			team.DryRunOutput = config.DryRunOutput
+		}
+		if team.CronSchedule == "" && len(team.Schedules) == 0 {
+			team.CronSchedule = config.CronSchedule
+			// Each team loads timezones and templates into its own copy of the schedules.
+			team.Schedules = slices.Clone(config.Schedules)
+		}
+		if team.CronTimezone == "" {
+			team.CronTimezone = config.CronTimezone
+		}
+		if team.Calendar.ICSFile == "" && team.Calendar.HolidaysFile == "" && len(team.Calendar.Holidays) == 0 {
+			team.Calendar = config.Calendar
+		}
+		if !team.OutOfOffice.GitLabStatus && team.OutOfOffice.File == "" {
+			team.OutOfOffice = config.OutOfOffice
+		}
+		if team.Timezone == "" {
+			team.Timezone = config.Timezone
+		}
+		team.team = team.Name
+
+		if team.CronSchedule != "" || len(team.Schedules) > 0 {
+			scheduled = true
+		}
+
+		v.prefix, v.scope = path+".", fmt.Sprintf("team %q: ", team.Name)
+		v.validateConfig(&team.Config)
+		v.prefix, v.scope = "", ""
+	}
+
+	if scheduled {
+		for i, team := range config.Teams {
+			if team.CronSchedule == "" && len(team.Schedules) == 0 {
+				v.errorf(fmt.Sprintf("teams.%d", i), "team %q: cron_schedule or schedules are required when other teams are scheduled", team.Name)
+			}
+		}
+	}
+}
+
+// parseTimeout parses a positive duration of the setting at path.
+func (v *configValidator) parseTimeout(path, value string) time.Duration {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		v.errorf(path, "invalid %s %q, must be a positive duration such as 30s or 2m", path, value)
+	}
+	return timeout
+}
+
+// withRunTimeout returns a context cancelled after the run timeout, if it is configured.
+func (c *Config) withRunTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.runTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.runTimeout)
+}
+
+// teams returns configurations of all teams, or the configuration itself if teams are not used.
+func (c *Config) teams() []*Config {
+	if len(c.Teams) == 0 {
+		return []*Config{c}
+	}
+
+	teams := make([]*Config, len(c.Teams))
+	for i := range c.Teams {
+		teams[i] = &c.Teams[i].Config
+	}
+	return teams
+}
+
+// scheduled reports whether a cron schedule is configured, for any of the teams.
+func (c *Config) scheduled() bool {
+	for _, team := range c.teams() {
+		if team.CronSchedule != "" || len(team.Schedules) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// forSchedule returns a copy of the configuration with the template and
+// destinations of the schedule applied.
+func (c *Config) forSchedule(schedule *ConfigSchedule) *Config {
+	config := *c
+	if schedule.summaryTemplate != nil {
+		config.summaryTemplate = schedule.summaryTemplate
+	}
+	if len(schedule.Destinations) > 0 {
+		config.keepDestinations(schedule.Destinations)
+	}
+	return &config
+}
+
+// selectTeam drops all teams except the named one.
+func (c *Config) selectTeam(name string) error {
+	for _, team := range c.Teams {
+		if team.Name == name {
+			c.Teams = []ConfigTeam{team}
+			return nil
+		}
+	}
+	return fmt.Errorf("team %q is not configured", name)
+}
+
+// hasOtherDestinations reports whether any destination besides Slack is configured.
+func (c *Config) hasOtherDestinations() bool {
+	return c.GitLabIssue.ProjectID != 0 || c.GitLabWiki.ProjectID != 0 || c.Email.Host != "" ||
+		c.Discord.WebhookURL != "" || c.Mattermost.WebhookURL != "" || c.GoogleChat.WebhookURL != "" ||
+		c.Webhook.URL != "" || c.Telegram.ChatID != "" || c.Matrix.RoomID != ""
+}
+
+// readConfig strictly decodes the configuration file in the given format,
+// rejecting unknown keys and interpolating environment variables in values.
+// Along with the configuration it returns the document node used to find line
+// numbers of invalid values. Decoding problems are returned as configErrors
+// together with the partially decoded configuration.
+func readConfig(file, format string, env Env) (*Config, *yaml.Node, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var config Config
-	err = yaml.Unmarshal(data, &config)
+	root, err := parseConfigDocument(data, format)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Unknown keys are looked for before interpolation, so that they are
+	// reported even if interpolation of their values fails.
+	errs := unknownFields(root, reflect.TypeOf(Config{}))
+
+	errs = append(errs, interpolateNode(root, env)...)
+
+	config := &Config{}
+	if len(root.Content) > 0 {
+		err := root.Decode(config)
+		if msgs := yamlErrors(err); msgs != nil {
+			for _, msg := range msgs {
+				errs = append(errs, parseYAMLError(msg))
+			}
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(errs) > 0 {
+		return config, root, errs
+	}
+	return config, root, nil
+}
+
+// yamlErrors returns messages of a decoding type error, or nil for other errors.
+func yamlErrors(err error) []string {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Errors
+	}
+	return nil
+}
+
+var yamlErrorRegexp = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// parseYAMLError splits the line number from a decoding error.
+func parseYAMLError(msg string) configError {
+	var line int
+	if m := yamlErrorRegexp.FindStringSubmatch(msg); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		msg = m[2]
 	}
+	return configError{line: line, message: msg}
+}
 
-	return &config, nil
+// unknownFields reports keys of the document not matching any field of the
+// struct decoded from their mapping.
+func unknownFields(node *yaml.Node, t reflect.Type) configErrors {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs configErrors
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			errs = append(errs, unknownFields(n, t)...)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, n := range node.Content {
+				errs = append(errs, unknownFields(n, t.Elem())...)
+			}
+		}
+	case yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch {
+			case t.Kind() == reflect.Map:
+				errs = append(errs, unknownFields(value, t.Elem())...)
+			case t.Kind() != reflect.Struct || key.Tag == "!!merge":
+			case fields[key.Value] == nil:
+				errs = append(errs, configError{line: key.Line, message: fmt.Sprintf("unknown field %q", key.Value)})
+			default:
+				errs = append(errs, unknownFields(value, fields[key.Value])...)
+			}
+		}
+	}
+	return errs
+}
+
+// yamlFields returns types of struct fields by their YAML keys, including
+// fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		switch {
+		case name == "-":
+		case opts == "inline":
+			for name, typ := range yamlFields(field.Type) {
+				fields[name] = typ
+			}
+		case name == "":
+			fields[strings.ToLower(field.Name)] = field.Type
+		default:
+			fields[name] = field.Type
+		}
+	}
+	return fields
+}
+
+// configError is a single problem in the configuration. Line is 0 for
+// problems not related to a particular line of the configuration file.
+type configError struct {
+	line    int
+	message string
+}
+
+func (e configError) String() string {
+	if e.line == 0 {
+		return e.message
+	}
+	return fmt.Sprintf("line %d: %s", e.line, e.message)
+}
+
+// configErrors holds all problems found in the configuration.
+type configErrors []configError
+
+func (e configErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].String()
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d configuration errors:", len(e))
+	for _, err := range e {
+		sb.WriteString("\n  - " + err.String())
+	}
+	return sb.String()
+}
+
+// configValidator collects configuration problems, annotating them with
+// line numbers of the configuration file if it was read.
+type configValidator struct {
+	root *yaml.Node
+	errs configErrors
+	// prefix is prepended to paths, and scope to messages, of recorded problems.
+	prefix string
+	scope  string
+}
+
+// errorf records a problem with the value at path, a dot separated list of
+// keys and sequence indices, e.g. "reports.1.format". Empty path means the
+// problem is not related to a single value.
+func (v *configValidator) errorf(path string, format string, args ...any) {
+	path = strings.TrimSuffix(v.prefix+path, ".")
+	v.errs = append(v.errs, configError{line: v.line(path), message: v.scope + fmt.Sprintf(format, args...)})
+}
+
+// check records err, if not nil, as a problem with the value at path.
+func (v *configValidator) check(path string, err error) {
+	if err != nil {
+		v.errorf(path, "%v", err)
+	}
+}
+
+// checkURL records a problem if the value at path is set but is not an absolute HTTP(S) URL.
+func (v *configValidator) checkURL(path, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.errorf(path, "invalid %s %q, must be an absolute http or https URL", path, value)
+	}
+}
+
+func (v *configValidator) validateIDs(config *Config) {
+	for i, project := range config.Projects {
+		if project.ID <= 0 {
+			v.errorf(fmt.Sprintf("projects.%d", i), "projects[%d].id must be a positive number", i)
+		}
+	}
+
+	for i, group := range config.Groups {
+		if group.ID <= 0 {
+			v.errorf(fmt.Sprintf("groups.%d", i), "groups[%d].id must be a positive number", i)
+		}
+	}
+
+	for i, author := range config.Authors {
+		path := fmt.Sprintf("authors.%d", i)
+		switch {
+		case author.ID != 0 && author.Username != "":
+			v.errorf(path, "authors[%d]: id and username are mutually exclusive", i)
+		case author.ID < 0:
+			v.errorf(path, "authors[%d].id must be a positive number", i)
+		case author.ID == 0 && author.Username == "":
+			v.errorf(path, "authors[%d]: either id or username is required", i)
+		}
+	}
+}
+
+// line returns the line of the value at path in the configuration file, or 0 if it is not there.
+func (v *configValidator) line(path string) int {
+	if v.root == nil || len(v.root.Content) == 0 || path == "" {
+		return 0
+	}
+
+	node := v.root.Content[0]
+	for _, key := range strings.Split(path, ".") {
+		node = childNode(node, key)
+		if node == nil {
+			return 0
+		}
+	}
+	return node.Line
+}
+
+func childNode(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	}
+	return nil
 }
 
 func parseIDsAsConfigProjects(env string) ([]ConfigProject, error) {
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFormatYAML = "yaml"
+	ConfigFormatJSON = "json"
+	ConfigFormatTOML = "toml"
+)
+
+// configFormat returns the format of the configuration file set by
+// CONFIG_FORMAT, or detected by its extension. YAML is assumed for unknown extensions.
+func configFormat(path string, env Env) (string, error) {
+	if format := strings.ToLower(env.Getenv("CONFIG_FORMAT")); format != "" {
+		switch format {
+		case ConfigFormatYAML, ConfigFormatJSON, ConfigFormatTOML:
+			return format, nil
+		case "yml":
+			return ConfigFormatYAML, nil
+		default:
+			return "", fmt.Errorf("invalid CONFIG_FORMAT %q, must be one of: %s, %s, %s",
+				format, ConfigFormatYAML, ConfigFormatJSON, ConfigFormatTOML)
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ConfigFormatJSON, nil
+	case ".toml":
+		return ConfigFormatTOML, nil
+	default:
+		return ConfigFormatYAML, nil
+	}
+}
+
+// parseConfigDocument parses the configuration file into a YAML document node,
+// so that all formats are interpolated, decoded and validated the same way.
+func parseConfigDocument(data []byte, format string) (*yaml.Node, error) {
+	switch format {
+	case ConfigFormatJSON:
+		return parseJSONDocument(data)
+	case ConfigFormatTOML:
+		return parseTOMLDocument(data)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// parseJSONDocument relies on JSON being a subset of YAML, which keeps line
+// numbers of values. The document is checked to be valid JSON first, so that
+// YAML is not accepted in JSON files.
+func parseJSONDocument(data []byte) (*yaml.Node, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line := bytes.Count(data[:syntaxErr.Offset], []byte{'\n'}) + 1
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// tomlDocument builds a YAML node tree from TOML expressions.
+type tomlDocument struct {
+	parser *unstable.Parser
+	root   *yaml.Node
+}
+
+// parseTOMLDocument converts a TOML document into YAML nodes, keeping line
+// numbers of keys and values.
+func parseTOMLDocument(data []byte) (*yaml.Node, error) {
+	doc := &tomlDocument{
+		parser: &unstable.Parser{},
+		root:   &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1},
+	}
+	doc.parser.Reset(data)
+
+	table := doc.root
+	for doc.parser.NextExpression() {
+		expr := doc.parser.Expression()
+
+		var err error
+		switch expr.Kind {
+		case unstable.KeyValue:
+			err = doc.setKeyValue(table, expr)
+		case unstable.Table:
+			table, err = doc.table(doc.root, expr.Key())
+		case unstable.ArrayTable:
+			table, err = doc.arrayTable(expr.Key())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := doc.parser.Error(); err != nil {
+		var parserErr *unstable.ParserError
+		if errors.As(err, &parserErr) {
+			return nil, fmt.Errorf("line %d: %s", doc.parser.Shape(doc.parser.Range(parserErr.Highlight)).Start.Line, parserErr.Message)
+		}
+		return nil, err
+	}
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1, Content: []*yaml.Node{doc.root}}, nil
+}
+
+// line returns the line of a TOML node, or fallback for nodes not keeping their position.
+func (d *tomlDocument) line(node *unstable.Node, fallback int) int {
+	if node.Raw.Length == 0 {
+		return fallback
+	}
+	return d.parser.Shape(node.Raw).Start.Line
+}
+
+// keyParts returns names of the dotted key parts and the line of the key.
+func (d *tomlDocument) keyParts(key unstable.Iterator) ([]string, int) {
+	var parts []string
+	line := 0
+	for key.Next() {
+		parts = append(parts, string(key.Node().Data))
+		line = d.line(key.Node(), line)
+	}
+	return parts, line
+}
+
+// child returns the value of a key of mapping, nil if it is not set.
+func child(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func appendMappingValue(mapping *yaml.Node, key string, line int, value *yaml.Node) {
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: line, Column: 1}, value)
+}
+
+// descend returns the table at keys relative to mapping, creating missing
+// tables. Arrays of tables resolve to their last element.
+func descend(mapping *yaml.Node, keys []string, line int) (*yaml.Node, error) {
+	for _, key := range keys {
+		next := child(mapping, key)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: 1}
+			appendMappingValue(mapping, key, line, next)
+		}
+		if next.Kind == yaml.SequenceNode && len(next.Content) > 0 {
+			next = next.Content[len(next.Content)-1]
+		}
+		if next.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("line %d: key %q is not a table", line, key)
+		}
+		mapping = next
+	}
+	return mapping, nil
+}
+
+func (d *tomlDocument) table(mapping *yaml.Node, key unstable.Iterator) (*yaml.Node, error) {
+	parts, line := d.keyParts(key)
+	return descend(mapping, parts, line)
+}
+
+func (d *tomlDocument) arrayTable(key unstable.Iterator) (*yaml.Node, error) {
+	parts, line := d.keyParts(key)
+
+	parent, err := descend(d.root, parts[:len(parts)-1], line)
+	if err != nil {
+		return nil, err
+	}
+
+	name := parts[len(parts)-1]
+	seq := child(parent, name)
+	if seq == nil {
+		seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line, Column: 1}
+		appendMappingValue(parent, name, line, seq)
+	}
+	if seq.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: key %q is not an array of tables", line, name)
+	}
+
+	table := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: 1}
+	seq.Content = append(seq.Content, table)
+	return table, nil
+}
+
+func (d *tomlDocument) setKeyValue(mapping *yaml.Node, expr *unstable.Node) error {
+	parts, line := d.keyParts(expr.Key())
+
+	parent, err := descend(mapping, parts[:len(parts)-1], line)
+	if err != nil {
+		return err
+	}
+
+	value, err := d.value(expr.Value(), line)
+	if err != nil {
+		return err
+	}
+	appendMappingValue(parent, parts[len(parts)-1], line, value)
+	return nil
+}
+
+// value converts a TOML value. Dates and times are kept as strings.
+func (d *tomlDocument) value(node *unstable.Node, line int) (*yaml.Node, error) {
+	line = d.line(node, line)
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Line: line, Column: 1}
+	}
+
+	switch node.Kind {
+	case unstable.String, unstable.LocalDate, unstable.LocalTime, unstable.LocalDateTime, unstable.DateTime:
+		return scalar("!!str", string(node.Data)), nil
+	case unstable.Bool:
+		return scalar("!!bool", string(node.Data)), nil
+	case unstable.Integer:
+		i, err := strconv.ParseInt(string(node.Data), 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid integer %s", line, node.Data)
+		}
+		return scalar("!!int", strconv.FormatInt(i, 10)), nil
+	case unstable.Float:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(string(node.Data), "_", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid float %s", line, node.Data)
+		}
+		switch {
+		case math.IsNaN(f):
+			return scalar("!!float", ".nan"), nil
+		case math.IsInf(f, 1):
+			return scalar("!!float", ".inf"), nil
+		case math.IsInf(f, -1):
+			return scalar("!!float", "-.inf"), nil
+		}
+		return scalar("!!float", strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case unstable.Array:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line, Column: 1}
+		children := node.Children()
+		for children.Next() {
+			item, err := d.value(children.Node(), line)
+			if err != nil {
+				return nil, err
+			}
+			seq.Content = append(seq.Content, item)
+		}
+		return seq, nil
+	case unstable.InlineTable:
+		mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: 1}
+		children := node.Children()
+		for children.Next() {
+			if err := d.setKeyValue(mapping, children.Node()); err != nil {
+				return nil, err
+			}
+		}
+		return mapping, nil
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported value %s", line, node.Kind)
+}
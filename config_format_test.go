@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		env      string
+		expected string
+	}{
+		{"config.yaml", "", ConfigFormatYAML},
+		{"config.yml", "", ConfigFormatYAML},
+		{"/etc/mergentle/config.JSON", "", ConfigFormatJSON},
+		{"config.toml", "", ConfigFormatTOML},
+		{"config", "", ConfigFormatYAML},
+		{"config.yaml", "json", ConfigFormatJSON},
+		{"config", "YML", ConfigFormatYAML},
+	}
+
+	for _, tt := range tests {
+		format, err := configFormat(tt.path, &MockEnv{values: map[string]string{"CONFIG_FORMAT": tt.env}})
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, format, tt.path)
+	}
+}
+
+func TestParseTOMLDocument(t *testing.T) {
+	root, err := parseTOMLDocument([]byte(`# Dotted keys, inline tables and nested arrays of tables.
+gitlab.token = "token"
+dry_run = true
+priority = { top = 0x0a, weights = { age = 1.5, approvals_missing = 2 } }
+
+[webhook]
+url = "https://example.com/hook"
+retries = 1_0
+headers = { "X-Team" = "backend" }
+
+[[teams]]
+name = "backend"
+authors = [{ username = "janedoe" }, { id = 918 }]
+
+  [[teams.groups]]
+  id = 1
+
+  [teams.slack]
+  webhook_url = "https://hooks.slack.com/services/backend"
+
+[[teams]]
+name = "mobile"
+`))
+	require.NoError(t, err)
+
+	var config Config
+	require.NoError(t, root.Decode(&config))
+
+	assert.Equal(t, "token", config.GitLab.Token)
+	assert.True(t, config.DryRun)
+	assert.Equal(t, 10, config.Priority.Top)
+	assert.Equal(t, 1.5, config.Priority.Weights.Age)
+	assert.Equal(t, 2.0, config.Priority.Weights.ApprovalsMissing)
+	assert.Equal(t, "https://example.com/hook", config.Webhook.URL)
+	require.NotNil(t, config.Webhook.Retries)
+	assert.Equal(t, 10, *config.Webhook.Retries)
+	assert.Equal(t, map[string]string{"X-Team": "backend"}, config.Webhook.Headers)
+
+	require.Len(t, config.Teams, 2)
+	assert.Equal(t, "backend", config.Teams[0].Name)
+	assert.Equal(t, []ConfigAuthor{{Username: "janedoe"}, {ID: 918}}, config.Teams[0].Authors)
+	assert.Equal(t, []ConfigGroup{{ID: 1}}, config.Teams[0].Groups)
+	assert.Equal(t, "https://hooks.slack.com/services/backend", config.Teams[0].Slack.WebhookURL)
+	assert.Equal(t, "mobile", config.Teams[1].Name)
+
+	assert.Empty(t, unknownFields(root, reflect.TypeOf(Config{})))
+	assert.Equal(t, 11, findKeyLine(root.Content[0], "teams"))
+}
+
+func TestParseTOMLDocumentErrors(t *testing.T) {
+	_, err := parseTOMLDocument([]byte("gitlab = \"x\"\n\n[gitlab.token]\n"))
+	assert.EqualError(t, err, `line 3: key "gitlab" is not a table`)
+
+	_, err = parseTOMLDocument([]byte("teams = 1\n[[teams]]\n"))
+	assert.EqualError(t, err, `line 2: key "teams" is not an array of tables`)
+}
+
+func findKeyLine(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i].Line
+		}
+	}
+	return 0
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,7 +33,7 @@ func TestLoadConfig(t *testing.T) {
 	t.Run("default GitLab URL", func(t *testing.T) {
 		env := &MockEnv{values: map[string]string{
 			"GITLAB_TOKEN":      "token",
-			"SLACK_WEBHOOK_URL": "webhook",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
 			"CONFIG_PATH":       "NONEXISTING.yaml",
 			"PROJECTS":          "1,2,3",
 		}}
@@ -44,10 +48,10 @@ func TestLoadConfig(t *testing.T) {
 		env := &MockEnv{values: map[string]string{
 			"GITLAB_URL":        "https://gitlab.example.com",
 			"GITLAB_TOKEN":      "token",
-			"SLACK_WEBHOOK_URL": "webhook",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
 			"CONFIG_PATH":       "NONEXISTING.yaml",
 			"PROJECTS":          "1,2,3",
-			"CRON_SCHEDULE":     "0 1 * * *",
+			"CRON_SCHEDULE":     "0 0 1 * * *",
 			"AUTHORS":           "1,username,123",
 		}}
 
@@ -59,7 +63,7 @@ func TestLoadConfig(t *testing.T) {
 			{ID: 2},
 			{ID: 3},
 		}, config.Projects)
-		assert.Equal(t, "0 1 * * *", config.CronSchedule)
+		assert.Equal(t, "0 0 1 * * *", config.CronSchedule)
 		assert.Equal(t, []ConfigAuthor{
 			{ID: 1},
 			{Username: "username"},
@@ -68,30 +72,573 @@ func TestLoadConfig(t *testing.T) {
 	})
 
 	// Test loading config from file
-	t.Run("loading from config file", func(t *testing.T) {
+	for _, path := range []string{"config.test.yaml", "config.test.json", "config.test.toml"} {
+		t.Run("loading from config file "+path, func(t *testing.T) {
+			env := &MockEnv{values: map[string]string{
+				"CONFIG_PATH": path,
+			}}
+
+			config, err := loadConfig(env)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "https://gitlab.example.com", config.GitLab.URL)
+			assert.Equal(t, "abcdef1234567890", config.GitLab.Token)
+			assert.Equal(t, "https://hooks.slack.com/services/your-slack-webhook-url", config.Slack.WebhookURL)
+			assert.Equal(t, []ConfigProject{
+				{ID: 123},
+				{ID: 456},
+			}, config.Projects)
+			assert.Equal(t, []ConfigGroup{
+				{ID: 1},
+				{ID: 2},
+			}, config.Groups)
+			assert.Equal(t, "0 0 7,13 * * 1-5", config.CronSchedule)
+			assert.Equal(t, []ConfigAuthor{
+				{Username: "janedoe"},
+				{Username: "johndoe"},
+				{ID: 918},
+			}, config.Authors)
+		})
+	}
+
+	t.Run("format from CONFIG_FORMAT", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config")
+		data, err := os.ReadFile("config.test.toml")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
 		env := &MockEnv{values: map[string]string{
-			"CONFIG_PATH": "config.test.yaml",
+			"CONFIG_PATH":   path,
+			"CONFIG_FORMAT": "toml",
 		}}
 
 		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "abcdef1234567890", config.GitLab.Token)
+
+		env.values["CONFIG_FORMAT"] = "ini"
+		_, err = loadConfig(env)
+		assert.EqualError(t, err, `invalid CONFIG_FORMAT "ini", must be one of: yaml, json, toml`)
+	})
+
+	t.Run("timezone", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":      "token",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
+			"CONFIG_PATH":       "NONEXISTING.yaml",
+			"PROJECTS":          "1",
+			"TIMEZONE":          "Europe/Warsaw",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Warsaw", config.location.String())
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":      "token",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
+			"CONFIG_PATH":       "NONEXISTING.yaml",
+			"PROJECTS":          "1",
+			"TIMEZONE":          "Mars/Olympus",
+		}}
+
+		_, err := loadConfig(env)
+		assert.ErrorContains(t, err, "error loading timezone")
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":      "token",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
+			"CONFIG_PATH":       "NONEXISTING.yaml",
+			"PROJECTS":          "1",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, config.shutdownTimeout)
+
+		env.values["SHUTDOWN_TIMEOUT"] = "2m"
+		config, err = loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Minute, config.shutdownTimeout)
+
+		env.values["SHUTDOWN_TIMEOUT"] = "soon"
+		_, err = loadConfig(env)
+		assert.EqualError(t, err, `invalid shutdown_timeout "soon", must be a positive duration such as 30s or 2m`)
+	})
+
+	t.Run("run timeout", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":      "token",
+			"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/xxx",
+			"CONFIG_PATH":       "NONEXISTING.yaml",
+			"PROJECTS":          "1",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		ctx, cancel := config.withRunTimeout(context.Background())
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		cancel()
+
+		env.values["RUN_TIMEOUT"] = "5m"
+		config, err = loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, config.runTimeout)
+		ctx, cancel = config.withRunTimeout(context.Background())
+		_, ok = ctx.Deadline()
+		assert.True(t, ok)
+		cancel()
+
+		env.values["RUN_TIMEOUT"] = "-1s"
+		_, err = loadConfig(env)
+		assert.EqualError(t, err, `invalid run_timeout "-1s", must be a positive duration such as 30s or 2m`)
+	})
+
+	t.Run("dry run does not require slack webhook", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":   "token",
+			"CONFIG_PATH":    "NONEXISTING.yaml",
+			"PROJECTS":       "1",
+			"DRY_RUN":        "true",
+			"DRY_RUN_OUTPUT": "/tmp/payloads.json",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.True(t, config.DryRun)
+		assert.Equal(t, "/tmp/payloads.json", config.DryRunOutput)
+	})
+
+	t.Run("invalid dry run", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN": "token",
+			"CONFIG_PATH":  "NONEXISTING.yaml",
+			"PROJECTS":     "1",
+			"DRY_RUN":      "maybe",
+		}}
+
+		_, err := loadConfig(env)
+		assert.ErrorContains(t, err, "error parsing DRY_RUN environment variable")
+	})
+
+	t.Run("chat webhook does not require slack webhook", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"GITLAB_TOKEN":        "token",
+			"CONFIG_PATH":         "NONEXISTING.yaml",
+			"PROJECTS":            "1",
+			"DISCORD_WEBHOOK_URL": "https://discord.com/api/webhooks/1/x",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "https://discord.com/api/webhooks/1/x", config.Discord.WebhookURL)
+	})
+
+	t.Run("reports all problems with line numbers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  url: gitlab.example.com
+  token: token
+slack:
+  webhok_url: https://hooks.slack.com/services/xxx
+projects:
+  - id: 0
+authors:
+  - id: 1
+    username: johndoe
+  - {}
+cron_schedule: "0 7 * * 1-5"
+sort_by: size
+`), 0o644))
+
+		env := &MockEnv{values: map[string]string{
+			"CONFIG_PATH": path,
+			"DRY_RUN":     "true",
+		}}
+
+		_, err := loadConfig(env)
+		require.Error(t, err)
+		assert.Equal(t, `7 configuration errors:
+  - line 5: unknown field "webhok_url"
+  - line 2: invalid gitlab.url "gitlab.example.com", must be an absolute http or https URL
+  - line 12: invalid cron_schedule "0 7 * * 1-5": parse cron expression: invalid expression length
+  - line 13: invalid sort_by "size", must be one of: age, updated, project, author, approvals_missing, priority
+  - line 7: projects[0].id must be a positive number
+  - line 9: authors[0]: id and username are mutually exclusive
+  - line 11: authors[1]: either id or username is required`, err.Error())
+	})
+
+	t.Run("reports all problems with line numbers in JSON and TOML", func(t *testing.T) {
+		files := map[string]string{
+			"config.json": `{
+  "gitlab": {"url": "gitlab.example.com", "token": "${GITLAB_TOKEN}"},
+  "slack": {"webhok_url": "https://hooks.slack.com/services/xxx"},
+  "projects": [{"id": 0}],
+  "sort_by": "size"
+}
+`,
+			"config.toml": `sort_by = "size"
+
+[gitlab]
+url = "gitlab.example.com"
+token = "${GITLAB_TOKEN}"
+
+[slack]
+webhok_url = "https://hooks.slack.com/services/xxx"
+
+[[projects]]
+id = 0
+`,
+		}
+		expected := map[string]string{
+			"config.json": `6 configuration errors:
+  - line 3: unknown field "webhok_url"
+  - line 2: environment variable GITLAB_TOKEN is not set
+  - line 2: invalid gitlab.url "gitlab.example.com", must be an absolute http or https URL
+  - GITLAB_TOKEN environment variable is required
+  - line 5: invalid sort_by "size", must be one of: age, updated, project, author, approvals_missing, priority
+  - line 4: projects[0].id must be a positive number`,
+			"config.toml": `6 configuration errors:
+  - line 8: unknown field "webhok_url"
+  - line 5: environment variable GITLAB_TOKEN is not set
+  - line 4: invalid gitlab.url "gitlab.example.com", must be an absolute http or https URL
+  - GITLAB_TOKEN environment variable is required
+  - line 1: invalid sort_by "size", must be one of: age, updated, project, author, approvals_missing, priority
+  - line 10: projects[0].id must be a positive number`,
+		}
+
+		for name, content := range files {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+			env := &MockEnv{values: map[string]string{
+				"CONFIG_PATH": path,
+				"DRY_RUN":     "true",
+			}}
+
+			_, err := loadConfig(env)
+			assert.EqualError(t, err, expected[name], name)
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		files := map[string]string{
+			"config.json": "{\n  \"projects\": [\n    {\"id\": 1},\n  ]\n}\n",
+			"config.toml": "[gitlab]\ntoken = \"token\"\nurl = \n",
+		}
+		expected := map[string]string{
+			"config.json": "error reading configuration file: line 4: invalid character ']' looking for beginning of value",
+			"config.toml": "error reading configuration file: line 3: incomplete number",
+		}
+
+		for name, content := range files {
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+			_, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+			assert.EqualError(t, err, expected[name], name)
+		}
+	})
+
+	t.Run("type errors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("projects:\n  - id: abc\n"), 0o644))
+
+		env := &MockEnv{values: map[string]string{
+			"CONFIG_PATH":  path,
+			"GITLAB_TOKEN": "token",
+			"DRY_RUN":      "true",
+		}}
+
+		_, err := loadConfig(env)
+		assert.EqualError(t, err, "2 configuration errors:\n  - line 2: cannot unmarshal !!str `abc` into int\n  - line 2: projects[0].id must be a positive number")
+	})
+
+	t.Run("example config", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"CONFIG_PATH": "config.yaml.example",
+		}}
+
+		_, err := loadConfig(env)
 		assert.NoError(t, err)
+	})
 
-		assert.Equal(t, "https://gitlab.example.com", config.GitLab.URL)
-		assert.Equal(t, "abcdef1234567890", config.GitLab.Token)
-		assert.Equal(t, "https://hooks.slack.com/services/your-slack-webhook-url", config.Slack.WebhookURL)
-		assert.Equal(t, []ConfigProject{
-			{ID: 123},
-			{ID: 456},
-		}, config.Projects)
-		assert.Equal(t, []ConfigGroup{
-			{ID: 1},
-			{ID: 2},
-		}, config.Groups)
-		assert.Equal(t, "0 7,13 * * 1-5", config.CronSchedule)
-		assert.Equal(t, []ConfigAuthor{
-			{Username: "janedoe"},
-			{Username: "johndoe"},
-			{ID: 918},
-		}, config.Authors)
+	t.Run("teams", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+cron_schedule: "0 0 9 * * 1-5"
+timezone: Europe/Warsaw
+teams:
+  - name: backend
+    groups:
+      - id: 1
+    slack:
+      webhook_url: https://hooks.slack.com/services/backend
+    sort_by: age
+  - name: mobile
+    projects:
+      - id: 2
+    cron_schedule: "0 30 8 * * 1-5"
+    discord:
+      webhook_url: https://discord.com/api/webhooks/1/x
+`), 0o644))
+
+		env := &MockEnv{values: map[string]string{"CONFIG_PATH": path}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+
+		teams := config.teams()
+		require.Len(t, teams, 2)
+		assert.Equal(t, "backend", teams[0].team)
+		assert.Equal(t, "token", teams[0].GitLab.Token)
+		assert.Equal(t, "https://gitlab.com", teams[0].GitLab.URL)
+		assert.Equal(t, "0 0 9 * * 1-5", teams[0].CronSchedule)
+		assert.Equal(t, "Europe/Warsaw", teams[0].location.String())
+		assert.Equal(t, SortByAge, teams[0].SortBy)
+		assert.Equal(t, "0 30 8 * * 1-5", teams[1].CronSchedule)
+		assert.Equal(t, []ConfigProject{{ID: 2}}, teams[1].Projects)
+		assert.True(t, config.scheduled())
+
+		env.values["TEAM"] = "mobile"
+		config, err = loadConfig(env)
+		require.NoError(t, err)
+		require.Len(t, config.teams(), 1)
+		assert.Equal(t, "mobile", config.teams()[0].team)
+
+		env.values["TEAM"] = "web"
+		_, err = loadConfig(env)
+		assert.EqualError(t, err, `team "web" is not configured`)
+	})
+
+	t.Run("invalid teams", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+projects:
+  - id: 1
+teams:
+  - name: backend
+    groups:
+      - id: 1
+    cron_schedule: "0 0 9 * * 1-5"
+    dry_run: true
+  - name: backend
+    dry_run: true
+    sort_by: size
+`), 0o644))
+
+		env := &MockEnv{values: map[string]string{"CONFIG_PATH": path}}
+
+		_, err := loadConfig(env)
+		assert.EqualError(t, err, `5 configuration errors:
+  - projects and groups must be configured per team when teams are used
+  - line 11: duplicate team name "backend"
+  - line 13: team "backend": invalid sort_by "size", must be one of: age, updated, project, author, approvals_missing, priority
+  - line 11: team "backend": neither groups nor projects were provided
+  - line 11: team "backend": cron_schedule or schedules are required when other teams are scheduled`)
+	})
+
+	t.Run("schedules", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "ping.tmpl"), []byte("{{ len .MergeRequests }} merge requests"), 0o644))
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+projects:
+  - id: 1
+slack:
+  webhook_url: https://hooks.slack.com/services/xxx
+discord:
+  webhook_url: https://discord.com/api/webhooks/1/x
+cron_timezone: Europe/Warsaw
+schedules:
+  - cron: "0 0 9 * * 1-5"
+  - cron: "0 0 13 * * 1-5"
+    timezone: America/New_York
+    template_file: `+filepath.Join(dir, "ping.tmpl")+`
+    destinations: [slack]
+`), 0o644))
+
+		env := &MockEnv{values: map[string]string{"CONFIG_PATH": path}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+
+		assert.True(t, config.scheduled())
+		assert.Equal(t, "Europe/Warsaw", config.cronLocation.String())
+		require.Len(t, config.Schedules, 2)
+		assert.Equal(t, "Europe/Warsaw", config.Schedules[0].location.String())
+		assert.Nil(t, config.Schedules[0].summaryTemplate)
+		assert.Equal(t, "America/New_York", config.Schedules[1].location.String())
+		assert.NotNil(t, config.Schedules[1].summaryTemplate)
+
+		env.values["CRON_TIMEZONE"] = "Asia/Tokyo"
+		config, err = loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", config.Schedules[0].location.String())
+	})
+
+	t.Run("per team settings at the top level", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+authors:
+  - username: johndoe
+sort_by: age
+template: "{{ len .Sections }}"
+reports:
+  - format: csv
+    path: report.csv
+teams:
+  - name: backend
+    projects:
+      - id: 1
+    slack:
+      webhook_url: https://hooks.slack.com/services/backend
+`), 0o644))
+
+		_, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+		assert.EqualError(t, err, `4 configuration errors:
+  - line 4: authors must be configured per team when teams are used
+  - line 5: sort_by must be configured per team when teams are used
+  - line 6: template must be configured per team when teams are used
+  - line 8: reports must be configured per team when teams are used`)
+	})
+
+	t.Run("teams inheriting schedules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+schedules:
+  - cron: "0 0 9 * * *"
+teams:
+  - name: a
+    cron_timezone: Europe/Warsaw
+    projects:
+      - id: 1
+    slack:
+      webhook_url: https://hooks.slack.com/services/a
+  - name: b
+    cron_timezone: America/New_York
+    projects:
+      - id: 2
+    slack:
+      webhook_url: https://hooks.slack.com/services/b
+`), 0o644))
+
+		config, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+		require.NoError(t, err)
+
+		jobs := cronJobs(config)
+		require.Len(t, jobs, 2)
+		assert.Equal(t, "Europe/Warsaw", jobs[0].location.String())
+		assert.Equal(t, []string{"a"}, teamNames(jobs[0].teams))
+		assert.Equal(t, "America/New_York", jobs[1].location.String())
+		assert.Equal(t, []string{"b"}, teamNames(jobs[1].teams))
+	})
+
+	t.Run("invalid schedules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+projects:
+  - id: 1
+slack:
+  webhook_url: https://hooks.slack.com/services/xxx
+cron_timezone: Mars/Olympus
+schedules:
+  - timezone: Europe/Warsaw
+  - cron: "0 0 13 * * 1-5"
+    timezone: Europe/Nowhere
+    destinations: [slack, email, irc]
+`), 0o644))
+
+		env := &MockEnv{values: map[string]string{"CONFIG_PATH": path}}
+
+		_, err := loadConfig(env)
+		assert.EqualError(t, err, `5 configuration errors:
+  - line 7: error loading cron_timezone: unknown time zone Mars/Olympus
+  - line 9: schedules[0].cron is required
+  - line 11: error loading schedules[1].timezone: unknown time zone Europe/Nowhere
+  - line 12: schedules[1]: destination "email" is not configured
+  - line 12: invalid schedules[1].destinations[2] "irc", must be one of: slack, gitlab_issue, gitlab_wiki, email, discord, mattermost, google_chat, webhook, telegram, matrix`)
+	})
+
+	t.Run("calendar", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+calendar:
+  holidays:
+    - 2024-12-25
+    - date: 2024-12-26
+      name: Boxing Day
+teams:
+  - name: backend
+    projects:
+      - id: 1
+    dry_run: true
+  - name: mobile
+    projects:
+      - id: 2
+    dry_run: true
+    calendar:
+      holidays:
+        - 2024-12-24
+`), 0o644))
+
+		config, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+		require.NoError(t, err)
+
+		christmas := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+		_, ok := config.Teams[0].calendar.holiday(christmas)
+		assert.True(t, ok)
+		_, ok = config.Teams[1].calendar.holiday(christmas)
+		assert.False(t, ok)
+
+		name, ok := config.Teams[0].calendar.holiday(christmas.AddDate(0, 0, 1))
+		assert.True(t, ok)
+		assert.Equal(t, "Boxing Day", name)
+	})
+
+	t.Run("invalid calendar", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+projects:
+  - id: 1
+dry_run: true
+calendar:
+  holidays:
+    - christmas
+`), 0o644))
+
+		_, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+		assert.EqualError(t, err, `line 7: error in calendar configuration: invalid holiday date "christmas", must be in YYYY-MM-DD format`)
+	})
+
+	t.Run("invalid out-of-office file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "ooo.yaml"), []byte("- username: janedoe\n  from: tomorrow\n"), 0o644))
+
+		path := filepath.Join(dir, "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`gitlab:
+  token: token
+projects:
+  - id: 1
+dry_run: true
+out_of_office:
+  file: `+filepath.Join(dir, "ooo.yaml")+`
+`), 0o644))
+
+		_, err := loadConfig(&MockEnv{values: map[string]string{"CONFIG_PATH": path}})
+		assert.EqualError(t, err, `line 7: error in out_of_office configuration: invalid date "tomorrow" of janedoe in out-of-office file, must be in YYYY-MM-DD format`)
 	})
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// Destination delivers the merge request summary somewhere.
+type Destination interface {
+	Name() string
+	// Send delivers the summary of merge requests, which may be empty.
+	Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error
+}
+
+// newDestinations creates destinations enabled in the configuration.
+func newDestinations(config *Config, client GitLabClient) []Destination {
+	var destinations []Destination
+
+	if config.GitLabIssue.ProjectID != 0 {
+		destinations = append(destinations, &gitLabIssueDestination{config: config, client: client})
+	}
+
+	if config.GitLabWiki.ProjectID != 0 {
+		destinations = append(destinations, &gitLabWikiDestination{config: config, client: client})
+	}
+
+	if config.Email.Host != "" {
+		destinations = append(destinations, &emailDestination{config: config})
+	}
+
+	if config.Discord.WebhookURL != "" {
+		destinations = append(destinations, newDiscordDestination(config))
+	}
+
+	if config.Mattermost.WebhookURL != "" {
+		destinations = append(destinations, newMattermostDestination(config))
+	}
+
+	if config.GoogleChat.WebhookURL != "" {
+		destinations = append(destinations, newGoogleChatDestination(config))
+	}
+
+	if config.Webhook.URL != "" {
+		destinations = append(destinations, newWebhookDestination(config))
+	}
+
+	if config.Telegram.ChatID != "" {
+		destinations = append(destinations, &telegramDestination{config: config})
+	}
+
+	if config.Matrix.RoomID != "" {
+		destinations = append(destinations, newMatrixDestination(config))
+	}
+
+	// In dry run mode Slack payload is printed even without the webhook URL,
+	// unless other destinations are configured.
+	if config.Slack.WebhookURL != "" || (config.DryRun && len(destinations) == 0) {
+		destinations = append([]Destination{&slackDestination{config: config, client: newSlackClient(config)}}, destinations...)
+	}
+
+	return destinations
+}
+
+// destinationSections are names of configuration sections of destinations,
+// used to select destinations of a schedule.
+var destinationSections = []string{
+	"slack", "gitlab_issue", "gitlab_wiki", "email", "discord", "mattermost", "google_chat", "webhook", "telegram", "matrix",
+}
+
+// hasDestination reports whether the destination of the section is
+// configured. Slack is always available in dry run mode.
+func (c *Config) hasDestination(section string) bool {
+	switch section {
+	case "slack":
+		return c.Slack.WebhookURL != "" || c.DryRun
+	case "gitlab_issue":
+		return c.GitLabIssue.ProjectID != 0
+	case "gitlab_wiki":
+		return c.GitLabWiki.ProjectID != 0
+	case "email":
+		return c.Email.Host != ""
+	case "discord":
+		return c.Discord.WebhookURL != ""
+	case "mattermost":
+		return c.Mattermost.WebhookURL != ""
+	case "google_chat":
+		return c.GoogleChat.WebhookURL != ""
+	case "webhook":
+		return c.Webhook.URL != ""
+	case "telegram":
+		return c.Telegram.ChatID != ""
+	case "matrix":
+		return c.Matrix.RoomID != ""
+	}
+	return false
+}
+
+// keepDestinations removes configuration of destinations not in sections.
+func (c *Config) keepDestinations(sections []string) {
+	keep := func(section string) bool { return slices.Contains(sections, section) }
+
+	if !keep("slack") {
+		c.Slack.WebhookURL = ""
+	}
+	if !keep("gitlab_issue") {
+		c.GitLabIssue = ConfigGitLabIssue{}
+	}
+	if !keep("gitlab_wiki") {
+		c.GitLabWiki = ConfigGitLabWiki{}
+	}
+	if !keep("email") {
+		c.Email = ConfigEmail{}
+	}
+	if !keep("discord") {
+		c.Discord = ConfigDiscord{}
+	}
+	if !keep("mattermost") {
+		c.Mattermost = ConfigMattermost{}
+	}
+	if !keep("google_chat") {
+		c.GoogleChat = ConfigGoogleChat{}
+	}
+	if !keep("webhook") {
+		c.Webhook = ConfigWebhook{}
+	}
+	if !keep("telegram") {
+		c.Telegram = ConfigTelegram{}
+	}
+	if !keep("matrix") {
+		c.Matrix = ConfigMatrix{}
+	}
+}
+
+// sendToDestinations sends the summary to all destinations, continuing after
+// failures and returning the first error.
+func sendToDestinations(ctx context.Context, destinations []Destination, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	var firstErr error
+	for _, destination := range destinations {
+		if err := destination.Send(ctx, mrs, now); err != nil {
+			log.Printf("Error sending merge request summary to %s: %v", destination.Name(), err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error sending to %s: %w", destination.Name(), err)
+			}
+		}
+	}
+	return firstErr
+}
+
+type slackDestination struct {
+	config *Config
+	client SlackClient
+}
+
+func (d *slackDestination) Name() string {
+	return "Slack"
+}
+
+func (d *slackDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	summary, err := formatMergeRequestsSummary(mrs, d.config, now)
+	if err != nil {
+		return fmt.Errorf("error rendering merge requests summary: %w", err)
+	}
+
+	if err := sendSlackMessage(ctx, d.client, summary); err != nil {
+		return fmt.Errorf("error sending Slack message: %w", err)
+	}
+
+	if d.config.DryRun {
+		log.Println("Dry run: merge request summary was not sent to Slack.")
+	} else {
+		log.Println("Successfully sent merge request summary to Slack.")
+	}
+	return nil
+}
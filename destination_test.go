@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexoid/mergentle-reminder/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func destinationNames(destinations []Destination) []string {
+	var names []string
+	for _, destination := range destinations {
+		names = append(names, destination.Name())
+	}
+	return names
+}
+
+func TestNewDestinations(t *testing.T) {
+	client := mocks.NewGitLabClient(t)
+
+	t.Run("slack", func(t *testing.T) {
+		config := &Config{}
+		config.Slack.WebhookURL = "https://hooks.slack.com/services/xxx"
+		assert.Equal(t, []string{"Slack"}, destinationNames(newDestinations(config, client)))
+	})
+
+	t.Run("gitlab only", func(t *testing.T) {
+		config := &Config{
+			GitLabIssue: ConfigGitLabIssue{ProjectID: 1},
+			GitLabWiki:  ConfigGitLabWiki{ProjectID: 1},
+			DryRun:      true,
+		}
+		assert.Equal(t, []string{"GitLab issue", "GitLab wiki"}, destinationNames(newDestinations(config, client)))
+	})
+
+	t.Run("email", func(t *testing.T) {
+		config := &Config{Email: ConfigEmail{Host: "smtp.example.com"}}
+		config.Slack.WebhookURL = "https://hooks.slack.com/services/xxx"
+		assert.Equal(t, []string{"Slack", "email"}, destinationNames(newDestinations(config, client)))
+	})
+
+	t.Run("chat webhooks", func(t *testing.T) {
+		config := &Config{
+			Discord:    ConfigDiscord{WebhookURL: "https://discord.com/api/webhooks/1/x"},
+			Mattermost: ConfigMattermost{WebhookURL: "https://mattermost.example.com/hooks/x"},
+			GoogleChat: ConfigGoogleChat{WebhookURL: "https://chat.googleapis.com/v1/spaces/x/messages"},
+		}
+		assert.Equal(t, []string{"Discord", "Mattermost", "Google Chat"}, destinationNames(newDestinations(config, client)))
+	})
+
+	t.Run("dry run without destinations", func(t *testing.T) {
+		config := &Config{DryRun: true}
+		assert.Equal(t, []string{"Slack"}, destinationNames(newDestinations(config, client)))
+	})
+}
+
+type testDestination struct {
+	name string
+	err  error
+	sent int
+}
+
+func (d *testDestination) Name() string {
+	return d.name
+}
+
+func (d *testDestination) Send(_ context.Context, _ []*MergeRequestWithApprovals, _ time.Time) error {
+	d.sent++
+	return d.err
+}
+
+func TestSendToDestinations(t *testing.T) {
+	failing := &testDestination{name: "first", err: errors.New("boom")}
+	succeeding := &testDestination{name: "second"}
+
+	err := sendToDestinations(context.Background(), []Destination{failing, succeeding}, nil, time.Now())
+
+	assert.EqualError(t, err, "error sending to first: boom")
+	assert.Equal(t, 1, failing.sent)
+	assert.Equal(t, 1, succeeding.sent, "other destinations are still sent to")
+}
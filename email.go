@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EmailTLSStartTLS = "starttls"
+	EmailTLSImplicit = "tls"
+	EmailTLSNone     = "none"
+)
+
+// emailMessage is a rendered email, also written as is in dry run mode.
+type emailMessage struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html"`
+}
+
+// emailDestination sends a multipart email to every recipient, containing
+// only merge requests matching the recipient's filters.
+type emailDestination struct {
+	config *Config
+}
+
+func (d *emailDestination) Name() string {
+	return "email"
+}
+
+func (d *emailDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	cfg := d.config.Email
+
+	catalog, err := catalogFor(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, recipient := range cfg.Recipients {
+		recipientMRs := filterMergeRequestsForRecipient(mrs, recipient)
+		if len(recipientMRs) == 0 {
+			continue
+		}
+
+		summary := buildSummary(recipientMRs, d.config, catalog, now)
+
+		text, err := renderMarkdownReport(summary)
+		if err != nil {
+			return fmt.Errorf("error rendering text body: %w", err)
+		}
+
+		html, err := renderHTMLReport(summary)
+		if err != nil {
+			return fmt.Errorf("error rendering HTML body: %w", err)
+		}
+
+		subject := cfg.Subject
+		if subject == "" {
+			subject = catalog.T("report_title")
+		}
+
+		msg := &emailMessage{
+			From:    cfg.From,
+			To:      []string{recipient.Address},
+			Subject: subject,
+			Text:    text,
+			HTML:    html,
+		}
+
+		if d.config.DryRun {
+			if err := writeDryRunPayload(d.config.DryRunOutput, msg); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := buildEmail(msg, now)
+		if err != nil {
+			return fmt.Errorf("error building email: %w", err)
+		}
+
+		// A failed delivery must not keep the digest from the remaining recipients.
+		if err := sendEmail(ctx, cfg, msg.To, data); err != nil {
+			log.Printf("Error sending email to %s: %v", recipient.Address, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error sending email to %s: %w", recipient.Address, err)
+			}
+			continue
+		}
+
+		log.Printf("Successfully sent merge request summary to %s.", recipient.Address)
+	}
+
+	return firstErr
+}
+
+// filterMergeRequestsForRecipient keeps merge requests matching any of the
+// recipient's projects or groups, all of them if no filters are set.
+func filterMergeRequestsForRecipient(mrs []*MergeRequestWithApprovals, recipient ConfigEmailRecipient) []*MergeRequestWithApprovals {
+	if len(recipient.Projects) == 0 && len(recipient.Groups) == 0 {
+		return mrs
+	}
+
+	var filtered []*MergeRequestWithApprovals
+	for _, mr := range mrs {
+		if slices.Contains(recipient.Projects, mr.MergeRequest.ProjectID) {
+			filtered = append(filtered, mr)
+			continue
+		}
+
+		for _, group := range recipient.Groups {
+			if strings.HasPrefix(mr.ProjectName, strings.TrimSuffix(group, "/")+"/") {
+				filtered = append(filtered, mr)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// buildEmail encodes the message as multipart/alternative with plain text and HTML parts.
+func buildEmail(msg *emailMessage, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	for _, part := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(w)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&data, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&data, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&data, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&data, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&data, "Content-Type: multipart/alternative; boundary=%s\r\n", mw.Boundary())
+	fmt.Fprintf(&data, "\r\n")
+	data.Write(body.Bytes())
+
+	return data.Bytes(), nil
+}
+
+// sendEmail delivers an encoded message over SMTP using the configured TLS mode and credentials.
+func sendEmail(ctx context.Context, cfg ConfigEmail, to []string, data []byte) error {
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if cfg.TLS == EmailTLSImplicit {
+			port = 465
+		}
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+
+	var conn net.Conn
+	var err error
+	if cfg.TLS == EmailTLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The SMTP client doesn't take a context, closing the connection aborts it.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if cfg.TLS == "" || cfg.TLS == EmailTLSStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+
+	if cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func validateEmail(cfg ConfigEmail) error {
+	if cfg.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if cfg.From == "" {
+		return fmt.Errorf("from is required")
+	}
+	if len(cfg.Recipients) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+	for _, recipient := range cfg.Recipients {
+		if recipient.Address == "" {
+			return fmt.Errorf("recipient address is required")
+		}
+	}
+	switch cfg.TLS {
+	case "", EmailTLSStartTLS, EmailTLSImplicit, EmailTLSNone:
+	default:
+		return fmt.Errorf("invalid tls %q, must be one of: %s, %s, %s", cfg.TLS, EmailTLSStartTLS, EmailTLSImplicit, EmailTLSNone)
+	}
+	_, err := catalogFor(cfg.Language)
+	return err
+}
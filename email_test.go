@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEmail struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStub serves a minimal plaintext SMTP dialog on a random local port
+// and reports every delivered message on the returned channel. Recipients
+// listed in rejected are refused.
+func startSMTPStub(t *testing.T, rejected ...string) (string, int, <-chan receivedEmail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan receivedEmail, 10)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTPStub(conn, received, rejected)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func receiveEmail(t *testing.T, received <-chan receivedEmail) receivedEmail {
+	t.Helper()
+
+	select {
+	case msg := <-received:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for email")
+		return receivedEmail{}
+	}
+}
+
+func serveSMTPStub(conn net.Conn, received chan<- receivedEmail, rejected []string) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+
+	reply("220 localhost ESMTP")
+
+	var msg receivedEmail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			msg.auth = line
+			reply("235 Authenticated")
+		case "MAIL":
+			msg.from = line
+			reply("250 OK")
+		case "RCPT":
+			if slices.ContainsFunc(rejected, func(address string) bool { return strings.Contains(line, "<"+address+">") }) {
+				reply("550 No such user")
+				continue
+			}
+			msg.to = append(msg.to, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var sb strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				sb.WriteString(dataLine)
+			}
+			msg.data = sb.String()
+			received <- msg
+			msg = receivedEmail{}
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	mrs := testMergeRequestsForExport()
+	mrs[0].MergeRequest.ProjectID = 42
+
+	host, port, received := startSMTPStub(t)
+
+	config := &Config{
+		Email: ConfigEmail{
+			Host:     host,
+			Port:     port,
+			TLS:      EmailTLSNone,
+			Username: "bot",
+			Password: "secret",
+			From:     "bot@example.com",
+			Subject:  "Morning digest",
+			Recipients: []ConfigEmailRecipient{
+				{Address: "alpha@example.com", Projects: []int{42}},
+				{Address: "nobody@example.com", Groups: []string{"other"}},
+				{Address: "all@example.com", Groups: []string{"group"}},
+			},
+		},
+	}
+
+	destination := &emailDestination{config: config}
+	require.NoError(t, destination.Send(context.Background(), mrs, now))
+
+	alpha := receiveEmail(t, received)
+	assert.Equal(t, "MAIL FROM:<bot@example.com>", alpha.from)
+	assert.Equal(t, []string{"RCPT TO:<alpha@example.com>"}, alpha.to)
+	assert.True(t, strings.HasPrefix(alpha.auth, "AUTH PLAIN"))
+
+	text, html := parseTestEmail(t, alpha.data, "Morning digest")
+	assert.Contains(t, text, "## group/alpha")
+	assert.NotContains(t, text, "group/beta")
+	assert.Contains(t, html, "<h2>group/alpha</h2>")
+
+	all := receiveEmail(t, received)
+	assert.Equal(t, []string{"RCPT TO:<all@example.com>"}, all.to)
+
+	text, _ = parseTestEmail(t, all.data, "Morning digest")
+	assert.Contains(t, text, "## group/alpha")
+	assert.Contains(t, text, "## group/beta")
+
+	assert.Empty(t, received)
+}
+
+func TestEmailDestinationError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	config := &Config{
+		Email: ConfigEmail{
+			Host:       "127.0.0.1",
+			Port:       port,
+			TLS:        EmailTLSNone,
+			From:       "bot@example.com",
+			Recipients: []ConfigEmailRecipient{{Address: "all@example.com"}},
+		},
+	}
+
+	destination := &emailDestination{config: config}
+	err = destination.Send(context.Background(), testMergeRequestsForExport(), time.Now())
+	assert.ErrorContains(t, err, "error sending email to all@example.com")
+}
+
+func TestEmailDestinationRejectedRecipient(t *testing.T) {
+	host, port, received := startSMTPStub(t, "gone@example.com")
+
+	config := &Config{
+		Email: ConfigEmail{
+			Host:       host,
+			Port:       port,
+			TLS:        EmailTLSNone,
+			From:       "bot@example.com",
+			Recipients: []ConfigEmailRecipient{{Address: "gone@example.com"}, {Address: "all@example.com"}},
+		},
+	}
+
+	destination := &emailDestination{config: config}
+	err := destination.Send(context.Background(), testMergeRequestsForExport(), time.Now())
+	assert.ErrorContains(t, err, "error sending email to gone@example.com: 550")
+
+	// Recipients after the rejected one still get the digest.
+	msg := receiveEmail(t, received)
+	assert.Equal(t, []string{"RCPT TO:<all@example.com>"}, msg.to)
+}
+
+// parseTestEmail checks headers of the message and returns its plain text and HTML parts.
+func parseTestEmail(t *testing.T, data, subject string) (string, string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+
+	decoded, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, subject, decoded)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		contentType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		parts[contentType] = string(content)
+	}
+
+	return parts["text/plain"], parts["text/html"]
+}
+
+func TestFilterMergeRequestsForRecipient(t *testing.T) {
+	mrs := testMergeRequestsForExport()
+
+	assert.Len(t, filterMergeRequestsForRecipient(mrs, ConfigEmailRecipient{}), len(mrs))
+	assert.Empty(t, filterMergeRequestsForRecipient(mrs, ConfigEmailRecipient{Groups: []string{"group/alph"}}))
+
+	assert.Empty(t, filterMergeRequestsForRecipient(mrs, ConfigEmailRecipient{Groups: []string{"group/beta"}}))
+	assert.Equal(t, mrs, filterMergeRequestsForRecipient(mrs, ConfigEmailRecipient{Groups: []string{"group/"}}))
+
+	mrs[0].MergeRequest.ProjectID = 42
+	assert.Equal(t, mrs[:1], filterMergeRequestsForRecipient(mrs, ConfigEmailRecipient{Projects: []int{42}}))
+}
+
+func TestValidateEmail(t *testing.T) {
+	valid := ConfigEmail{Host: "smtp.example.com", From: "bot@example.com", Recipients: []ConfigEmailRecipient{{Address: "a@example.com"}}}
+	assert.NoError(t, validateEmail(valid))
+
+	invalid := valid
+	invalid.TLS = "ssl"
+	assert.ErrorContains(t, validateEmail(invalid), `invalid tls "ssl"`)
+
+	invalid = valid
+	invalid.From = ""
+	assert.ErrorContains(t, validateEmail(invalid), "from is required")
+
+	invalid = valid
+	invalid.Recipients = []ConfigEmailRecipient{{}}
+	assert.ErrorContains(t, validateEmail(invalid), "recipient address is required")
+
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+
+	// exportSchemaVersion is bumped on every incompatible change of the export schema.
+	exportSchemaVersion = 1
+)
+
+// Export is the document written in JSON export mode.
+type Export struct {
+	SchemaVersion int                    `json:"schema_version"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	MergeRequests []ExportedMergeRequest `json:"merge_requests"`
+}
+
+// ExportedMergeRequest is a single merge request in JSON and CSV exports.
+// CSV columns follow the JSON field names, lists are joined with ";".
+type ExportedMergeRequest struct {
+	Project                  string    `json:"project"`
+	IID                      int       `json:"iid"`
+	Title                    string    `json:"title"`
+	URL                      string    `json:"url"`
+	Author                   string    `json:"author"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+	AgeSeconds               int64     `json:"age_seconds"`
+	State                    string    `json:"state"`
+	ApprovedBy               []string  `json:"approved_by"`
+	ApprovalsLeft            int       `json:"approvals_left"`
+	Reviewers                []string  `json:"reviewers"`
+	Labels                   []string  `json:"labels"`
+	Score                    float64   `json:"score"`
+	HasUnresolvedDiscussions bool      `json:"has_unresolved_discussions"`
+	HasConflicts             bool      `json:"has_conflicts"`
+	PipelineStatus           string    `json:"pipeline_status"`
+}
+
+var exportCSVHeader = []string{
+	"project", "iid", "title", "url", "author", "created_at", "updated_at", "age_seconds", "state",
+	"approved_by", "approvals_left", "reviewers", "labels", "score",
+	"has_unresolved_discussions", "has_conflicts", "pipeline_status",
+}
+
+func newExport(mrs []*MergeRequestWithApprovals, now time.Time) *Export {
+	export := &Export{
+		SchemaVersion: exportSchemaVersion,
+		GeneratedAt:   now.UTC(),
+		MergeRequests: []ExportedMergeRequest{},
+	}
+
+	for _, mr := range mrs {
+		m := mr.MergeRequest
+		exported := ExportedMergeRequest{
+			Project:                  mr.ProjectName,
+			IID:                      m.IID,
+			Title:                    m.Title,
+			URL:                      m.WebURL,
+			State:                    classifyMergeRequest(mr).MessageKey(),
+			ApprovedBy:               nonNil(mr.ApprovedBy),
+			ApprovalsLeft:            mr.ApprovalsLeft,
+			Reviewers:                []string{},
+			Labels:                   nonNil(m.Labels),
+			Score:                    mr.Score,
+			HasUnresolvedDiscussions: !m.BlockingDiscussionsResolved,
+			HasConflicts:             m.HasConflicts,
+			PipelineStatus:           pipelineStatus(mr),
+		}
+
+		if m.Author != nil {
+			exported.Author = m.Author.Username
+		}
+		for _, reviewer := range m.Reviewers {
+			exported.Reviewers = append(exported.Reviewers, reviewer.Username)
+		}
+		if m.CreatedAt != nil {
+			exported.CreatedAt = m.CreatedAt.UTC()
+			exported.AgeSeconds = int64(now.Sub(*m.CreatedAt).Seconds())
+		}
+		if m.UpdatedAt != nil {
+			exported.UpdatedAt = m.UpdatedAt.UTC()
+		}
+
+		export.MergeRequests = append(export.MergeRequests, exported)
+	}
+
+	return export
+}
+
+func writeExport(w io.Writer, format string, export *Export) error {
+	switch format {
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+	case ExportFormatCSV:
+		return writeExportCSV(w, export)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func writeExportCSV(w io.Writer, export *Export) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	for _, mr := range export.MergeRequests {
+		err := cw.Write([]string{
+			mr.Project,
+			strconv.Itoa(mr.IID),
+			mr.Title,
+			mr.URL,
+			mr.Author,
+			mr.CreatedAt.Format(time.RFC3339),
+			mr.UpdatedAt.Format(time.RFC3339),
+			strconv.FormatInt(mr.AgeSeconds, 10),
+			mr.State,
+			strings.Join(mr.ApprovedBy, ";"),
+			strconv.Itoa(mr.ApprovalsLeft),
+			strings.Join(mr.Reviewers, ";"),
+			strings.Join(mr.Labels, ";"),
+			strconv.FormatFloat(mr.Score, 'f', -1, 64),
+			strconv.FormatBool(mr.HasUnresolvedDiscussions),
+			strconv.FormatBool(mr.HasConflicts),
+			mr.PipelineStatus,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportMergeRequests writes the export to the configured path, or stdout if it is empty.
+func exportMergeRequests(config *Config, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	export := newExport(mrs, now)
+
+	if config.Export.Path == "" {
+		return writeExport(os.Stdout, config.Export.Format, export)
+	}
+
+	f, err := os.Create(config.Export.Path)
+	if err != nil {
+		return err
+	}
+	if err := writeExport(f, config.Export.Format, export); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// nonNil makes empty lists encode as [] rather than null.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares actual with the content of testdata/name,
+// rewriting the file instead when the tests are run with -update.
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func testMergeRequestsForExport() []*MergeRequestWithApprovals {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 1, 12, 8, 30, 0, 0, time.UTC)
+
+	return []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 12, Title: "Add \"export\", finally", WebURL: "https://gitlab.com/group/alpha/-/merge_requests/12",
+				CreatedAt: &createdAt, UpdatedAt: &updatedAt, BlockingDiscussionsResolved: true,
+				Author:    &gitlab.BasicUser{Name: "John Doe", Username: "johndoe"},
+				Reviewers: []*gitlab.BasicUser{{Name: "Jane Doe", Username: "janedoe"}, {Name: "James Doe", Username: "jamesdoe"}},
+				Labels:    gitlab.Labels{"backend", "priority::high"},
+				Pipeline:  &gitlab.PipelineInfo{Status: "success"},
+			},
+			ProjectName:   "group/alpha",
+			ApprovedBy:    []string{"Jane Doe"},
+			ApprovalsLeft: 1,
+			Score:         12.5,
+		},
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 3, Title: "Fix typo", WebURL: "https://gitlab.com/group/beta/-/merge_requests/3",
+				CreatedAt: &createdAt, UpdatedAt: &createdAt, HasConflicts: true,
+				Author: &gitlab.BasicUser{Name: "Jane Doe", Username: "janedoe"},
+			},
+			ProjectName: "group/beta",
+		},
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	export := newExport(testMergeRequestsForExport(), now)
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, writeExport(&out, ExportFormatJSON, export))
+		assertGolden(t, "export.json", out.Bytes())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, writeExport(&out, ExportFormatCSV, export))
+		assertGolden(t, "export.csv", out.Bytes())
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		assert.ErrorContains(t, writeExport(&bytes.Buffer{}, "xml", export), `unsupported export format "xml"`)
+	})
+}
+
+func TestWriteExport_Empty(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	require.NoError(t, writeExport(&out, ExportFormatJSON, newExport(nil, now)))
+	assert.Contains(t, out.String(), `"merge_requests": []`)
+}
+
+func TestExportMergeRequests_File(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	config := &Config{}
+	config.Export.Format = ExportFormatCSV
+	config.Export.Path = filepath.Join(t.TempDir(), "export.csv")
+
+	require.NoError(t, exportMergeRequests(config, testMergeRequestsForExport(), now))
+
+	data, err := os.ReadFile(config.Export.Path)
+	require.NoError(t, err)
+	assertGolden(t, "export.csv", data)
+}
@@ -1,47 +1,123 @@
 package main
 
-import "github.com/xanzy/go-gitlab"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
 
 //go:generate mockery --name GitLabClient
 type GitLabClient interface {
-	ListGroupProjects(groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)
-	ListSubGroups(groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)
-	ListProjectMergeRequests(projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)
-	GetMergeRequestApprovalsConfiguration(projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)
+	ListGroupProjects(ctx context.Context, groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)
+	ListSubGroups(ctx context.Context, groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)
+	ListProjectMergeRequests(ctx context.Context, projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)
+	GetMergeRequestApprovalsConfiguration(ctx context.Context, projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)
+	GetMergeRequest(ctx context.Context, projectID int, mergeRequestID int, options *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error)
+	GetProject(ctx context.Context, projectID int, options *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error)
+	GetUserStatus(ctx context.Context, userID int, options ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error)
+	ListProjectIssues(ctx context.Context, projectID int, options *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, *gitlab.Response, error)
+	CreateIssue(ctx context.Context, projectID int, options *gitlab.CreateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)
+	UpdateIssue(ctx context.Context, projectID int, issueIID int, options *gitlab.UpdateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)
+	ListWikis(ctx context.Context, projectID int, options *gitlab.ListWikisOptions) ([]*gitlab.Wiki, *gitlab.Response, error)
+	GetWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.GetWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)
+	CreateWikiPage(ctx context.Context, projectID int, options *gitlab.CreateWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)
+	EditWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.EditWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)
 }
 
 type MergeRequestWithApprovals struct {
-	MergeRequest *gitlab.MergeRequest
-	ApprovedBy   []string
+	MergeRequest  *gitlab.MergeRequest
+	ProjectName   string
+	ApprovedBy    []string
+	ApprovalsLeft int
+	Score         float64
+	// AuthorAway and AwayReviewers tell which users are out of office, with names of the reviewers.
+	AuthorAway    bool
+	AwayReviewers []string
+	// SuggestedReviewer is the name of a replacement for away reviewers, may be empty.
+	SuggestedReviewer string
 }
 
 type gitLabClient struct {
 	client *gitlab.Client
 }
 
-func (c *gitLabClient) ListGroupProjects(groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
-	return c.client.Groups.ListGroupProjects(groupID, options)
+func (c *gitLabClient) ListGroupProjects(ctx context.Context, groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+	return c.client.Groups.ListGroupProjects(groupID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) ListSubGroups(ctx context.Context, groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error) {
+	return c.client.Groups.ListSubGroups(groupID, opt, append(options, gitlab.WithContext(ctx))...)
+}
+
+func (c *gitLabClient) ListProjectMergeRequests(ctx context.Context, projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+	return c.client.MergeRequests.ListProjectMergeRequests(projectID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) GetMergeRequestApprovalsConfiguration(ctx context.Context, projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
+	return c.client.MergeRequestApprovals.GetConfiguration(projectID, mergeRequestID, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) GetMergeRequest(ctx context.Context, projectID int, mergeRequestID int, options *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error) {
+	return c.client.MergeRequests.GetMergeRequest(projectID, mergeRequestID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) GetProject(ctx context.Context, projectID int, options *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error) {
+	return c.client.Projects.GetProject(projectID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) GetUserStatus(ctx context.Context, userID int, options ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error) {
+	return c.client.Users.GetUserStatus(userID, append(options, gitlab.WithContext(ctx))...)
 }
 
-func (c *gitLabClient) ListSubGroups(groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error) {
-	return c.client.Groups.ListSubGroups(groupID, opt, options...)
+func (c *gitLabClient) ListProjectIssues(ctx context.Context, projectID int, options *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+	return c.client.Issues.ListProjectIssues(projectID, options, gitlab.WithContext(ctx))
 }
 
-func (c *gitLabClient) ListProjectMergeRequests(projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
-	return c.client.MergeRequests.ListProjectMergeRequests(projectID, options)
+func (c *gitLabClient) CreateIssue(ctx context.Context, projectID int, options *gitlab.CreateIssueOptions) (*gitlab.Issue, *gitlab.Response, error) {
+	return c.client.Issues.CreateIssue(projectID, options, gitlab.WithContext(ctx))
 }
 
-func (c *gitLabClient) GetMergeRequestApprovalsConfiguration(projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
-	return c.client.MergeRequestApprovals.GetConfiguration(projectID, mergeRequestID)
+func (c *gitLabClient) UpdateIssue(ctx context.Context, projectID int, issueIID int, options *gitlab.UpdateIssueOptions) (*gitlab.Issue, *gitlab.Response, error) {
+	return c.client.Issues.UpdateIssue(projectID, issueIID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) ListWikis(ctx context.Context, projectID int, options *gitlab.ListWikisOptions) ([]*gitlab.Wiki, *gitlab.Response, error) {
+	return c.client.Wikis.ListWikis(projectID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) GetWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.GetWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	return c.client.Wikis.GetWikiPage(projectID, slug, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) CreateWikiPage(ctx context.Context, projectID int, options *gitlab.CreateWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	return c.client.Wikis.CreateWikiPage(projectID, options, gitlab.WithContext(ctx))
+}
+
+func (c *gitLabClient) EditWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.EditWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	return c.client.Wikis.EditWikiPage(projectID, slug, options, gitlab.WithContext(ctx))
+}
+
+func newGitLabClient(config *Config) (*gitLabClient, error) {
+	glClient, err := gitlab.NewClient(config.GitLab.Token,
+		gitlab.WithBaseURL(config.GitLab.URL))
+	if err != nil {
+		return nil, fmt.Errorf("error creating GitLab client: %w", err)
+	}
+
+	return &gitLabClient{client: glClient}, nil
 }
 
-func fetchOpenedMergeRequests(config *Config, client GitLabClient) ([]*MergeRequestWithApprovals, error) {
+// resolveProjectIDs returns IDs of all configured projects, including projects
+// of the configured groups and their subgroups.
+func resolveProjectIDs(ctx context.Context, config *Config, client GitLabClient) ([]int, error) {
 	var groupIDs []int
 	for _, group := range config.Groups {
 		groupIDs = append(groupIDs, group.ID)
 
 		// Add subgroups to the groups list.
-		subgroupIDs, err := fetchSubGroups(group.ID, client)
+		subgroupIDs, err := fetchSubGroups(ctx, group.ID, client)
 		if err != nil {
 			return nil, err
 		}
@@ -50,7 +126,7 @@ func fetchOpenedMergeRequests(config *Config, client GitLabClient) ([]*MergeRequ
 	}
 
 	// Add projects from groups to the projects list.
-	projectIDs, err := fetchProjectsFromGroups(groupIDs, client)
+	projectIDs, err := fetchProjectsFromGroups(ctx, groupIDs, client)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +135,34 @@ func fetchOpenedMergeRequests(config *Config, client GitLabClient) ([]*MergeRequ
 		projectIDs = append(projectIDs, project.ID)
 	}
 
+	return projectIDs, nil
+}
+
+// resolveProjects is like resolveProjectIDs, but fetches details of every project.
+func resolveProjects(ctx context.Context, config *Config, client GitLabClient) ([]*gitlab.Project, error) {
+	projectIDs, err := resolveProjectIDs(ctx, config, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*gitlab.Project
+	for _, projectID := range projectIDs {
+		project, _, err := client.GetProject(ctx, projectID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching project %d: %w", projectID, err)
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+func fetchOpenedMergeRequests(ctx context.Context, config *Config, client GitLabClient) ([]*MergeRequestWithApprovals, error) {
+	projectIDs, err := resolveProjectIDs(ctx, config, client)
+	if err != nil {
+		return nil, err
+	}
+
 	var allMRs []*MergeRequestWithApprovals
 
 	for _, projectID := range projectIDs {
@@ -74,25 +178,36 @@ func fetchOpenedMergeRequests(config *Config, client GitLabClient) ([]*MergeRequ
 		}
 
 		for {
-			mrs, resp, err := client.ListProjectMergeRequests(projectID, options)
+			mrs, resp, err := client.ListProjectMergeRequests(ctx, projectID, options)
 			if err != nil {
 				return nil, err
 			}
 
 			for _, mr := range mrs {
-				approvals, _, err := client.GetMergeRequestApprovalsConfiguration(projectID, mr.IID)
+				approvals, _, err := client.GetMergeRequestApprovalsConfiguration(ctx, projectID, mr.IID)
 				if err != nil {
 					return nil, err
 				}
 
+				// The size of the diff is not included in the list response.
+				if config.Priority.Weights.Size != 0 {
+					details, _, err := client.GetMergeRequest(ctx, projectID, mr.IID, nil)
+					if err != nil {
+						return nil, err
+					}
+					mr.ChangesCount = details.ChangesCount
+				}
+
 				approvedBy := make([]string, len(approvals.ApprovedBy))
 				for i, approver := range approvals.ApprovedBy {
 					approvedBy[i] = approver.User.Name
 				}
 
 				allMRs = append(allMRs, &MergeRequestWithApprovals{
-					MergeRequest: mr,
-					ApprovedBy:   approvedBy,
+					MergeRequest:  mr,
+					ProjectName:   projectName(mr),
+					ApprovedBy:    approvedBy,
+					ApprovalsLeft: approvals.ApprovalsLeft,
 				})
 			}
 
@@ -107,7 +222,7 @@ func fetchOpenedMergeRequests(config *Config, client GitLabClient) ([]*MergeRequ
 	return allMRs, nil
 }
 
-func fetchProjectsFromGroups(groupIDs []int, client GitLabClient) ([]int, error) {
+func fetchProjectsFromGroups(ctx context.Context, groupIDs []int, client GitLabClient) ([]int, error) {
 	var projectIDs []int
 	for _, groupID := range groupIDs {
 		options := &gitlab.ListGroupProjectsOptions{
@@ -118,7 +233,7 @@ func fetchProjectsFromGroups(groupIDs []int, client GitLabClient) ([]int, error)
 		}
 
 		for {
-			projects, resp, err := client.ListGroupProjects(groupID, options)
+			projects, resp, err := client.ListGroupProjects(ctx, groupID, options)
 			if err != nil {
 				return nil, err
 			}
@@ -138,7 +253,7 @@ func fetchProjectsFromGroups(groupIDs []int, client GitLabClient) ([]int, error)
 	return projectIDs, nil
 }
 
-func fetchSubGroups(groupID int, client GitLabClient) ([]int, error) {
+func fetchSubGroups(ctx context.Context, groupID int, client GitLabClient) ([]int, error) {
 	var groupIDs []int
 
 	options := &gitlab.ListSubGroupsOptions{
@@ -149,7 +264,7 @@ func fetchSubGroups(groupID int, client GitLabClient) ([]int, error) {
 	}
 
 	for {
-		groups, resp, err := client.ListSubGroups(groupID, options)
+		groups, resp, err := client.ListSubGroups(ctx, groupID, options)
 		if err != nil {
 			return nil, err
 		}
@@ -167,3 +282,15 @@ func fetchSubGroups(groupID int, client GitLabClient) ([]int, error) {
 
 	return groupIDs, nil
 }
+
+// projectName extracts the full project path from the merge request reference,
+// e.g. "group/project" from "group/project!42".
+func projectName(mr *gitlab.MergeRequest) string {
+	if mr.References != nil && mr.References.Full != "" {
+		if i := strings.LastIndex(mr.References.Full, "!"); i > 0 {
+			return mr.References.Full[:i]
+		}
+		return mr.References.Full
+	}
+	return fmt.Sprintf("Project %d", mr.ProjectID)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// cachingGitLabClient remembers responses to read requests, so that projects
+// and groups shared by several teams are fetched once per run. Requests
+// modifying data, and reads of issues and wiki pages, are passed through.
+// It is not safe for concurrent use.
+type cachingGitLabClient struct {
+	GitLabClient
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value any
+	resp  *gitlab.Response
+}
+
+func newCachingGitLabClient(client GitLabClient) *cachingGitLabClient {
+	return &cachingGitLabClient{GitLabClient: client, entries: make(map[string]cacheEntry)}
+}
+
+// cached returns the remembered response for the key, or calls fetch and
+// remembers its response if it succeeds.
+func cached[T any](c *cachingGitLabClient, key string, fetch func() (T, *gitlab.Response, error)) (T, *gitlab.Response, error) {
+	if entry, ok := c.entries[key]; ok {
+		return entry.value.(T), entry.resp, nil
+	}
+
+	value, resp, err := fetch()
+	if err == nil {
+		c.entries[key] = cacheEntry{value: value, resp: resp}
+	}
+	return value, resp, err
+}
+
+// cacheKey identifies a request by the method name and its arguments, including
+// options which are encoded as JSON to compare them by value.
+func cacheKey(method string, args ...any) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Sprintf("%s%v", method, args)
+	}
+	return method + string(data)
+}
+
+func (c *cachingGitLabClient) ListGroupProjects(ctx context.Context, groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+	return cached(c, cacheKey("ListGroupProjects", groupID, options), func() ([]*gitlab.Project, *gitlab.Response, error) {
+		return c.GitLabClient.ListGroupProjects(ctx, groupID, options)
+	})
+}
+
+func (c *cachingGitLabClient) ListSubGroups(ctx context.Context, groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error) {
+	return cached(c, cacheKey("ListSubGroups", groupID, opt), func() ([]*gitlab.Group, *gitlab.Response, error) {
+		return c.GitLabClient.ListSubGroups(ctx, groupID, opt, options...)
+	})
+}
+
+func (c *cachingGitLabClient) ListProjectMergeRequests(ctx context.Context, projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+	return cached(c, cacheKey("ListProjectMergeRequests", projectID, options), func() ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+		return c.GitLabClient.ListProjectMergeRequests(ctx, projectID, options)
+	})
+}
+
+func (c *cachingGitLabClient) GetMergeRequestApprovalsConfiguration(ctx context.Context, projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
+	return cached(c, cacheKey("GetMergeRequestApprovalsConfiguration", projectID, mergeRequestID), func() (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
+		return c.GitLabClient.GetMergeRequestApprovalsConfiguration(ctx, projectID, mergeRequestID)
+	})
+}
+
+func (c *cachingGitLabClient) GetMergeRequest(ctx context.Context, projectID int, mergeRequestID int, options *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error) {
+	return cached(c, cacheKey("GetMergeRequest", projectID, mergeRequestID, options), func() (*gitlab.MergeRequest, *gitlab.Response, error) {
+		return c.GitLabClient.GetMergeRequest(ctx, projectID, mergeRequestID, options)
+	})
+}
+
+func (c *cachingGitLabClient) GetProject(ctx context.Context, projectID int, options *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error) {
+	return cached(c, cacheKey("GetProject", projectID, options), func() (*gitlab.Project, *gitlab.Response, error) {
+		return c.GitLabClient.GetProject(ctx, projectID, options)
+	})
+}
+
+func (c *cachingGitLabClient) GetUserStatus(ctx context.Context, userID int, options ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error) {
+	return cached(c, cacheKey("GetUserStatus", userID), func() (*gitlab.UserStatus, *gitlab.Response, error) {
+		return c.GitLabClient.GetUserStatus(ctx, userID, options...)
+	})
+}
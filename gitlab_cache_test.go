@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexoid/mergentle-reminder/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestCachingGitLabClient(t *testing.T) {
+	client := mocks.NewGitLabClient(t)
+	cache := newCachingGitLabClient(client)
+
+	firstPage := &gitlab.ListProjectMergeRequestsOptions{State: gitlab.String("opened"), ListOptions: gitlab.ListOptions{Page: 1}}
+	secondPage := &gitlab.ListProjectMergeRequestsOptions{State: gitlab.String("opened"), ListOptions: gitlab.ListOptions{Page: 2}}
+
+	client.EXPECT().ListProjectMergeRequests(mock.Anything, 1, firstPage).
+		Return([]*gitlab.MergeRequest{{IID: 1}}, &gitlab.Response{TotalPages: 2}, nil).Once()
+	client.EXPECT().ListProjectMergeRequests(mock.Anything, 1, secondPage).
+		Return([]*gitlab.MergeRequest{{IID: 2}}, &gitlab.Response{TotalPages: 2}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		// Equal options are matched by value, not by pointer.
+		mrs, resp, err := cache.ListProjectMergeRequests(context.Background(), 1, &gitlab.ListProjectMergeRequestsOptions{
+			State: gitlab.String("opened"), ListOptions: gitlab.ListOptions{Page: 1},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, mrs[0].IID)
+		assert.Equal(t, 2, resp.TotalPages)
+	}
+
+	mrs, _, err := cache.ListProjectMergeRequests(context.Background(), 1, secondPage)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mrs[0].IID)
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		client.EXPECT().GetProject(mock.Anything, 7, (*gitlab.GetProjectOptions)(nil)).Return(nil, nil, assert.AnError).Once()
+		client.EXPECT().GetProject(mock.Anything, 7, (*gitlab.GetProjectOptions)(nil)).Return(&gitlab.Project{ID: 7}, &gitlab.Response{}, nil).Once()
+
+		_, _, err := cache.GetProject(context.Background(), 7, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+
+		for i := 0; i < 2; i++ {
+			project, _, err := cache.GetProject(context.Background(), 7, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 7, project.ID)
+		}
+	})
+
+	t.Run("writes are passed through", func(t *testing.T) {
+		options := &gitlab.UpdateIssueOptions{Title: gitlab.String("MRs")}
+		client.EXPECT().UpdateIssue(mock.Anything, 1, 2, options).Return(&gitlab.Issue{IID: 2}, &gitlab.Response{}, nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			_, _, err := cache.UpdateIssue(context.Background(), 1, 2, options)
+			require.NoError(t, err)
+		}
+	})
+}
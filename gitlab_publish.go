@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+const defaultGitLabIssueLabel = "mergentle-reminder"
+
+// gitLabIssueDestination keeps the description of a single issue up to date
+// with the Markdown report. The issue is either configured explicitly by IID,
+// or found by its label among opened issues and created if there is none.
+type gitLabIssueDestination struct {
+	config *Config
+	client GitLabClient
+}
+
+func (d *gitLabIssueDestination) Name() string {
+	return "GitLab issue"
+}
+
+func (d *gitLabIssueDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	cfg := d.config.GitLabIssue
+
+	catalog, err := catalogFor(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	description, err := renderMarkdownReport(buildSummary(mrs, d.config, catalog, now))
+	if err != nil {
+		return fmt.Errorf("error rendering report: %w", err)
+	}
+
+	title := cfg.Title
+	if title == "" {
+		title = catalog.T("report_title")
+	}
+
+	label := cfg.Label
+	if label == "" {
+		label = defaultGitLabIssueLabel
+	}
+
+	issueIID := cfg.IssueIID
+	if issueIID == 0 {
+		issueIID, err = d.findIssue(ctx, label)
+		if err != nil {
+			return err
+		}
+	}
+
+	if issueIID == 0 {
+		options := &gitlab.CreateIssueOptions{
+			Title:       gitlab.String(title),
+			Description: gitlab.String(description),
+			Labels:      &gitlab.LabelOptions{label},
+		}
+
+		if d.config.DryRun {
+			return writeDryRunPayload(d.config.DryRunOutput, options)
+		}
+
+		issue, _, err := d.client.CreateIssue(ctx, cfg.ProjectID, options)
+		if err != nil {
+			return fmt.Errorf("error creating issue: %w", err)
+		}
+
+		log.Printf("Created GitLab issue %s", issue.WebURL)
+		return nil
+	}
+
+	options := &gitlab.UpdateIssueOptions{
+		Title:       gitlab.String(title),
+		Description: gitlab.String(description),
+	}
+
+	if d.config.DryRun {
+		return writeDryRunPayload(d.config.DryRunOutput, options)
+	}
+
+	issue, _, err := d.client.UpdateIssue(ctx, cfg.ProjectID, issueIID, options)
+	if err != nil {
+		return fmt.Errorf("error updating issue %d: %w", issueIID, err)
+	}
+
+	log.Printf("Updated GitLab issue %s", issue.WebURL)
+	return nil
+}
+
+// findIssue returns IID of the oldest opened issue with the label, or 0 if there is none.
+func (d *gitLabIssueDestination) findIssue(ctx context.Context, label string) (int, error) {
+	options := &gitlab.ListProjectIssuesOptions{
+		State:   gitlab.String("opened"),
+		Labels:  &gitlab.LabelOptions{label},
+		OrderBy: gitlab.String("created_at"),
+		Sort:    gitlab.String("asc"),
+		ListOptions: gitlab.ListOptions{
+			PerPage: 1,
+			Page:    1,
+		},
+	}
+
+	issues, _, err := d.client.ListProjectIssues(ctx, d.config.GitLabIssue.ProjectID, options)
+	if err != nil {
+		return 0, fmt.Errorf("error searching for issue: %w", err)
+	}
+
+	if len(issues) == 0 {
+		return 0, nil
+	}
+	return issues[0].IID, nil
+}
+
+// gitLabWikiDestination keeps a wiki page up to date with the Markdown report.
+type gitLabWikiDestination struct {
+	config *Config
+	client GitLabClient
+}
+
+func (d *gitLabWikiDestination) Name() string {
+	return "GitLab wiki"
+}
+
+func (d *gitLabWikiDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	cfg := d.config.GitLabWiki
+
+	catalog, err := catalogFor(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	content, err := renderMarkdownReport(buildSummary(mrs, d.config, catalog, now))
+	if err != nil {
+		return fmt.Errorf("error rendering report: %w", err)
+	}
+
+	title := cfg.Title
+	if title == "" {
+		title = catalog.T("report_title")
+	}
+
+	format := gitlab.WikiFormatMarkdown
+
+	if d.config.DryRun {
+		return writeDryRunPayload(d.config.DryRunOutput, &gitlab.EditWikiPageOptions{
+			Title:   gitlab.String(title),
+			Content: gitlab.String(content),
+			Format:  &format,
+		})
+	}
+
+	slug := cfg.Slug
+	if slug == "" {
+		slug, err = d.findPage(ctx, title)
+		if err != nil {
+			return err
+		}
+	} else if _, _, err := d.client.GetWikiPage(ctx, cfg.ProjectID, slug, nil); err != nil {
+		return fmt.Errorf("error fetching wiki page %s: %w", slug, err)
+	}
+
+	if slug == "" {
+		page, _, err := d.client.CreateWikiPage(ctx, cfg.ProjectID, &gitlab.CreateWikiPageOptions{
+			Title:   gitlab.String(title),
+			Content: gitlab.String(content),
+			Format:  &format,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating wiki page: %w", err)
+		}
+
+		log.Printf("Created GitLab wiki page %s", page.Slug)
+		return nil
+	}
+
+	page, _, err := d.client.EditWikiPage(ctx, cfg.ProjectID, slug, &gitlab.EditWikiPageOptions{
+		Title:   gitlab.String(title),
+		Content: gitlab.String(content),
+		Format:  &format,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating wiki page %s: %w", slug, err)
+	}
+
+	log.Printf("Updated GitLab wiki page %s", page.Slug)
+	return nil
+}
+
+// findPage returns the slug of the wiki page with the title, or an empty
+// string if there is none. The slug is not derived from the title, as GitLab
+// escapes and nests pages depending on the characters in it.
+func (d *gitLabWikiDestination) findPage(ctx context.Context, title string) (string, error) {
+	pages, _, err := d.client.ListWikis(ctx, d.config.GitLabWiki.ProjectID, nil)
+	if err != nil {
+		return "", fmt.Errorf("error listing wiki pages: %w", err)
+	}
+
+	for _, page := range pages {
+		if page.Title == title {
+			return page.Slug, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexoid/mergentle-reminder/mocks"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitLabIssueDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	mrs := testMergeRequestsForExport()
+
+	t.Run("creates issue if none found", func(t *testing.T) {
+		config := &Config{GitLabIssue: ConfigGitLabIssue{ProjectID: 10}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().ListProjectIssues(mock.Anything, 10, mock.MatchedBy(func(options *gitlab.ListProjectIssuesOptions) bool {
+			return *options.State == "opened" && (*options.Labels)[0] == "mergentle-reminder"
+		})).Return([]*gitlab.Issue{}, &gitlab.Response{}, nil).Once()
+
+		client.EXPECT().CreateIssue(mock.Anything, 10, mock.MatchedBy(func(options *gitlab.CreateIssueOptions) bool {
+			return *options.Title == "Open merge requests" &&
+				assert.Contains(t, *options.Description, "## group/alpha") &&
+				assert.Equal(t, gitlab.LabelOptions{"mergentle-reminder"}, *options.Labels)
+		})).Return(&gitlab.Issue{IID: 1}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabIssueDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), mrs, now))
+	})
+
+	t.Run("updates found issue", func(t *testing.T) {
+		config := &Config{GitLabIssue: ConfigGitLabIssue{ProjectID: 10, Label: "digest", Title: "MRs"}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().ListProjectIssues(mock.Anything, 10, mock.Anything).
+			Return([]*gitlab.Issue{{IID: 7}}, &gitlab.Response{}, nil).Once()
+
+		client.EXPECT().UpdateIssue(mock.Anything, 10, 7, mock.MatchedBy(func(options *gitlab.UpdateIssueOptions) bool {
+			return *options.Title == "MRs" && assert.Contains(t, *options.Description, "## group/beta")
+		})).Return(&gitlab.Issue{IID: 7}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabIssueDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), mrs, now))
+	})
+
+	t.Run("updates configured issue", func(t *testing.T) {
+		config := &Config{GitLabIssue: ConfigGitLabIssue{ProjectID: 10, IssueIID: 3}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().UpdateIssue(mock.Anything, 10, 3, mock.Anything).
+			Return(&gitlab.Issue{IID: 3}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabIssueDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), nil, now))
+	})
+}
+
+func TestGitLabWikiDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	mrs := testMergeRequestsForExport()
+
+	t.Run("creates missing page", func(t *testing.T) {
+		config := &Config{GitLabWiki: ConfigGitLabWiki{ProjectID: 10}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().ListWikis(mock.Anything, 10, (*gitlab.ListWikisOptions)(nil)).
+			Return([]*gitlab.Wiki{{Title: "Home", Slug: "home"}}, &gitlab.Response{}, nil).Once()
+
+		client.EXPECT().CreateWikiPage(mock.Anything, 10, mock.MatchedBy(func(options *gitlab.CreateWikiPageOptions) bool {
+			return *options.Title == "Open merge requests" && assert.Contains(t, *options.Content, "## group/alpha")
+		})).Return(&gitlab.Wiki{Slug: "Open-merge-requests"}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabWikiDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), mrs, now))
+	})
+
+	t.Run("edits existing page found by title", func(t *testing.T) {
+		// GitLab escapes special characters of the title in the slug.
+		config := &Config{GitLabWiki: ConfigGitLabWiki{ProjectID: 10, Title: "Review queue: backend?"}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().ListWikis(mock.Anything, 10, (*gitlab.ListWikisOptions)(nil)).
+			Return([]*gitlab.Wiki{{Title: "Review queue: backend?", Slug: "Review-queue:-backend%3F"}}, &gitlab.Response{}, nil).Once()
+
+		client.EXPECT().EditWikiPage(mock.Anything, 10, "Review-queue:-backend%3F", mock.MatchedBy(func(options *gitlab.EditWikiPageOptions) bool {
+			return *options.Title == "Review queue: backend?" && *options.Format == gitlab.WikiFormatMarkdown
+		})).Return(&gitlab.Wiki{Slug: "Review-queue:-backend%3F"}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabWikiDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), mrs, now))
+	})
+
+	t.Run("edits page with configured slug", func(t *testing.T) {
+		config := &Config{GitLabWiki: ConfigGitLabWiki{ProjectID: 10, Title: "Review queue", Slug: "team/review-queue"}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().GetWikiPage(mock.Anything, 10, "team/review-queue", (*gitlab.GetWikiPageOptions)(nil)).
+			Return(&gitlab.Wiki{Slug: "team/review-queue"}, &gitlab.Response{}, nil).Once()
+
+		client.EXPECT().EditWikiPage(mock.Anything, 10, "team/review-queue", mock.Anything).
+			Return(&gitlab.Wiki{Slug: "team/review-queue"}, &gitlab.Response{}, nil).Once()
+
+		destination := &gitLabWikiDestination{config: config, client: client}
+		assert.NoError(t, destination.Send(context.Background(), mrs, now))
+	})
+
+	t.Run("configured slug not found", func(t *testing.T) {
+		config := &Config{GitLabWiki: ConfigGitLabWiki{ProjectID: 10, Slug: "missing"}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().GetWikiPage(mock.Anything, 10, "missing", mock.Anything).
+			Return(nil, &gitlab.Response{}, gitlab.ErrNotFound).Once()
+
+		destination := &gitLabWikiDestination{config: config, client: client}
+		assert.ErrorContains(t, destination.Send(context.Background(), mrs, now), "error fetching wiki page missing")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		config := &Config{GitLabWiki: ConfigGitLabWiki{ProjectID: 10}}
+		client := mocks.NewGitLabClient(t)
+
+		client.EXPECT().ListWikis(mock.Anything, 10, mock.Anything).
+			Return(nil, nil, assert.AnError).Once()
+
+		destination := &gitLabWikiDestination{config: config, client: client}
+		assert.ErrorContains(t, destination.Send(context.Background(), mrs, now), "error listing wiki pages")
+	})
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/flexoid/mergentle-reminder/mocks"
 	"github.com/stretchr/testify/assert"
@@ -30,7 +34,7 @@ func TestFetchOpenedMergeRequests(t *testing.T) {
 		},
 	}
 
-	mockGitLabClient.On("ListProjectMergeRequests", config.Projects[0].ID, &options).Return(
+	mockGitLabClient.On("ListProjectMergeRequests", mock.Anything, config.Projects[0].ID, &options).Return(
 		[]*gitlab.MergeRequest{{IID: 1}},
 		&gitlab.Response{CurrentPage: 1, NextPage: 2, TotalPages: 2},
 		nil,
@@ -38,19 +42,19 @@ func TestFetchOpenedMergeRequests(t *testing.T) {
 
 	optionsForPage2 := options
 	optionsForPage2.Page = 2
-	mockGitLabClient.On("ListProjectMergeRequests", config.Projects[0].ID, &optionsForPage2).Return(
+	mockGitLabClient.On("ListProjectMergeRequests", mock.Anything, config.Projects[0].ID, &optionsForPage2).Return(
 		[]*gitlab.MergeRequest{{IID: 2}},
 		&gitlab.Response{CurrentPage: 2, TotalPages: 2},
 		nil,
 	).Once()
 
-	mockGitLabClient.On("ListProjectMergeRequests", config.Projects[1].ID, &options).Return(
+	mockGitLabClient.On("ListProjectMergeRequests", mock.Anything, config.Projects[1].ID, &options).Return(
 		[]*gitlab.MergeRequest{{IID: 3}},
 		&gitlab.Response{CurrentPage: 1, TotalPages: 1},
 		nil,
 	).Once()
 
-	mockGitLabClient.On("GetMergeRequestApprovalsConfiguration", config.Projects[0].ID, 1).Return(
+	mockGitLabClient.On("GetMergeRequestApprovalsConfiguration", mock.Anything, config.Projects[0].ID, 1).Return(
 		&gitlab.MergeRequestApprovals{
 			ApprovedBy: []*gitlab.MergeRequestApproverUser{
 				{User: &gitlab.BasicUser{Name: "John Doe"}},
@@ -61,7 +65,7 @@ func TestFetchOpenedMergeRequests(t *testing.T) {
 		nil,
 	).Once()
 
-	mockGitLabClient.On("GetMergeRequestApprovalsConfiguration", mock.AnythingOfType("int"), mock.AnythingOfType("int")).Return(
+	mockGitLabClient.On("GetMergeRequestApprovalsConfiguration", mock.Anything, mock.AnythingOfType("int"), mock.AnythingOfType("int")).Return(
 		&gitlab.MergeRequestApprovals{
 			ApprovedBy: []*gitlab.MergeRequestApproverUser{},
 		},
@@ -69,7 +73,7 @@ func TestFetchOpenedMergeRequests(t *testing.T) {
 		nil,
 	).Twice()
 
-	mrs, err := fetchOpenedMergeRequests(config, mockGitLabClient)
+	mrs, err := fetchOpenedMergeRequests(context.Background(), config, mockGitLabClient)
 
 	mockGitLabClient.AssertExpectations(t)
 	assert.NoError(t, err)
@@ -99,7 +103,7 @@ func TestFetchProjectsFromGroups(t *testing.T) {
 		},
 	}
 
-	mockGitLabClient.On("ListGroupProjects", groups[0], &options).Return(
+	mockGitLabClient.On("ListGroupProjects", mock.Anything, groups[0], &options).Return(
 		[]*gitlab.Project{
 			{ID: 1},
 			{ID: 2},
@@ -110,7 +114,7 @@ func TestFetchProjectsFromGroups(t *testing.T) {
 
 	optionsForPage2 := options
 	optionsForPage2.Page = 2
-	mockGitLabClient.On("ListGroupProjects", groups[0], &optionsForPage2).Return(
+	mockGitLabClient.On("ListGroupProjects", mock.Anything, groups[0], &optionsForPage2).Return(
 		[]*gitlab.Project{
 			{ID: 3},
 			{ID: 4},
@@ -119,7 +123,7 @@ func TestFetchProjectsFromGroups(t *testing.T) {
 		nil,
 	).Once()
 
-	mockGitLabClient.On("ListGroupProjects", groups[1], &options).Return(
+	mockGitLabClient.On("ListGroupProjects", mock.Anything, groups[1], &options).Return(
 		[]*gitlab.Project{
 			{ID: 5},
 		},
@@ -127,7 +131,7 @@ func TestFetchProjectsFromGroups(t *testing.T) {
 		nil,
 	).Once()
 
-	projectIDs, err := fetchProjectsFromGroups(groups, mockGitLabClient)
+	projectIDs, err := fetchProjectsFromGroups(context.Background(), groups, mockGitLabClient)
 
 	mockGitLabClient.AssertExpectations(t)
 	assert.NoError(t, err)
@@ -156,7 +160,7 @@ func TestFetchSubGroups(t *testing.T) {
 					{ID: 1},
 					{ID: 2},
 				}
-				mockClient.On("ListSubGroups", 1, &gitlab.ListSubGroupsOptions{
+				mockClient.On("ListSubGroups", mock.Anything, 1, &gitlab.ListSubGroupsOptions{
 					ListOptions: gitlab.ListOptions{
 						PerPage: 50,
 						Page:    1,
@@ -180,13 +184,13 @@ func TestFetchSubGroups(t *testing.T) {
 					{ID: 3},
 					{ID: 4},
 				}
-				mockClient.On("ListSubGroups", 1, &gitlab.ListSubGroupsOptions{
+				mockClient.On("ListSubGroups", mock.Anything, 1, &gitlab.ListSubGroupsOptions{
 					ListOptions: gitlab.ListOptions{
 						PerPage: 50,
 						Page:    1,
 					},
 				}).Return(groupsPage1, &gitlab.Response{TotalPages: 2, CurrentPage: 1, NextPage: 2}, nil)
-				mockClient.On("ListSubGroups", 1, &gitlab.ListSubGroupsOptions{
+				mockClient.On("ListSubGroups", mock.Anything, 1, &gitlab.ListSubGroupsOptions{
 					ListOptions: gitlab.ListOptions{
 						PerPage: 50,
 						Page:    2,
@@ -203,10 +207,64 @@ func TestFetchSubGroups(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			client := tc.mockClient()
-			groupIDs, err := fetchSubGroups(tc.groupID, client)
+			groupIDs, err := fetchSubGroups(context.Background(), tc.groupID, client)
 
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedIDs, groupIDs)
 		})
 	}
 }
+
+func TestFetchOpenedMergeRequests_FetchesSizeForPriority(t *testing.T) {
+	config := &Config{
+		Projects: []ConfigProject{{ID: 1}},
+	}
+	config.Priority.Weights.Size = 1
+
+	mockGitLabClient := mocks.NewGitLabClient(t)
+
+	mockGitLabClient.EXPECT().ListProjectMergeRequests(mock.Anything, 1, mock.Anything).Return(
+		[]*gitlab.MergeRequest{{IID: 7}},
+		&gitlab.Response{CurrentPage: 1, TotalPages: 1},
+		nil,
+	).Once()
+
+	mockGitLabClient.EXPECT().GetMergeRequestApprovalsConfiguration(mock.Anything, 1, 7).Return(
+		&gitlab.MergeRequestApprovals{ApprovalsLeft: 1},
+		&gitlab.Response{},
+		nil,
+	).Once()
+
+	mockGitLabClient.EXPECT().GetMergeRequest(mock.Anything, 1, 7, (*gitlab.GetMergeRequestsOptions)(nil)).Return(
+		&gitlab.MergeRequest{IID: 7, ChangesCount: "42"},
+		&gitlab.Response{},
+		nil,
+	).Once()
+
+	mrs, err := fetchOpenedMergeRequests(context.Background(), config, mockGitLabClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mrs))
+	assert.Equal(t, "42", mrs[0].MergeRequest.ChangesCount)
+	assert.Equal(t, 1, mrs[0].ApprovalsLeft)
+}
+
+func TestGitLabClient_Context(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client, err := newGitLabClient(&Config{GitLab: ConfigGitLab{URL: server.URL, Token: "token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = client.ListProjectMergeRequests(ctx, 1, &gitlab.ListProjectMergeRequestsOptions{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -8,7 +8,6 @@ require (
 	github.com/slack-go/slack v0.13.1
 	github.com/stretchr/testify v1.10.0
 	github.com/xanzy/go-gitlab v0.107.0
-	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -16,7 +15,10 @@ require (
 	github.com/aws/aws-lambda-go v1.49.0
 	github.com/aws/constructs-go/constructs/v10 v10.4.2
 	github.com/aws/jsii-runtime-go v1.112.0
+	github.com/fsnotify/fsnotify v1.5.4
+	github.com/pelletier/go-toml/v2 v2.2.4
 	github.com/reugn/go-quartz v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -42,5 +44,4 @@ require (
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
 	golang.org/x/tools v0.33.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
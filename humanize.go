@@ -0,0 +1,44 @@
+package main
+
+import "time"
+
+// humanizeAge formats the time passed between t and now, e.g. "4 days".
+// With businessDays set, weekends are not counted once the age exceeds a day.
+func humanizeAge(t, now time.Time, businessDays bool, catalog *Catalog) string {
+	d := now.Sub(t)
+	if !businessDays || d < 24*time.Hour {
+		return humanizeDuration(d, catalog)
+	}
+
+	return catalog.N("business_day", businessDaysBetween(t, now))
+}
+
+// humanizeDuration formats a duration in the largest whole unit, e.g. "4 days".
+func humanizeDuration(d time.Duration, catalog *Catalog) string {
+	switch {
+	case d < time.Minute:
+		return catalog.T("less_than_minute")
+	case d < time.Hour:
+		return catalog.N("minute", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return catalog.N("hour", int(d.Hours()))
+	default:
+		return catalog.N("day", int(d.Hours()/24))
+	}
+}
+
+// businessDaysBetween counts working days (Monday to Friday) after from's
+// calendar day up to and including to's calendar day, in to's location.
+func businessDaysBetween(from, to time.Time) int {
+	from = from.In(to.Location())
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, to.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location())
+
+	var days int
+	for day = day.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	assert.Equal(t, "less than a minute", humanizeDuration(30*time.Second, catalogs["en"]))
+	assert.Equal(t, "1 minute", humanizeDuration(time.Minute, catalogs["en"]))
+	assert.Equal(t, "5 hours", humanizeDuration(5*time.Hour+30*time.Minute, catalogs["en"]))
+	assert.Equal(t, "1 day", humanizeDuration(30*time.Hour, catalogs["en"]))
+	assert.Equal(t, "12 days", humanizeDuration(12*24*time.Hour, catalogs["en"]))
+}
+
+func TestHumanizeAge(t *testing.T) {
+	// Monday.
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		t            time.Time
+		businessDays bool
+		expected     string
+	}{
+		{name: "hours", t: now.Add(-3 * time.Hour), businessDays: true, expected: "3 hours"},
+		{name: "calendar days over weekend", t: time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC), expected: "3 days"},
+		{name: "business days over weekend", t: time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC), businessDays: true, expected: "1 business day"},
+		{name: "business days over two weeks", t: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), businessDays: true, expected: "10 business days"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, humanizeAge(tc.t, now, tc.businessDays, catalogs["en"]))
+		})
+	}
+}
+
+func TestBusinessDaysBetween_UsesTargetLocation(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	// Friday 23:30 UTC is already Saturday in Warsaw.
+	from := time.Date(2024, 1, 12, 23, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 15, 10, 0, 0, 0, warsaw)
+
+	assert.Equal(t, 1, businessDaysBetween(from, to))
+}
+
+func TestFormatMergeRequestsSummary_TimezoneAndRelativeDates(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 14, 13, 0, 0, 0, time.UTC)
+
+	mrs := []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				Title: "MR", WebURL: "https://gitlab.com/mr/1", CreatedAt: &createdAt, BlockingDiscussionsResolved: true,
+				Author: &gitlab.BasicUser{Name: "John Doe"},
+			},
+		},
+	}
+
+	t.Run("absolute", func(t *testing.T) {
+		config := &Config{location: warsaw}
+
+		summary, err := formatMergeRequestsSummary(mrs, config, now)
+		require.NoError(t, err)
+		assert.Contains(t, summary, "*Created at:* 10 January 2024, 13:00 CET\n")
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		config := &Config{location: warsaw, RelativeDates: true}
+
+		summary, err := formatMergeRequestsSummary(mrs, config, now)
+		require.NoError(t, err)
+		assert.Contains(t, summary, "*Opened:* 4 days ago\n")
+	})
+
+	t.Run("relative business days", func(t *testing.T) {
+		config := &Config{location: warsaw, RelativeDates: true, BusinessDays: true}
+
+		summary, err := formatMergeRequestsSummary(mrs, config, now)
+		require.NoError(t, err)
+		assert.Contains(t, summary, "*Opened:* 2 business days ago\n")
+	})
+}
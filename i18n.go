@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const defaultLanguage = "en"
+
+// Catalog holds translated messages for a single language.
+type Catalog struct {
+	Language string
+	// messages are format strings passed to fmt.Sprintf.
+	messages map[string]string
+	// plurals holds plural forms of a message, indexed by the result of pluralForm.
+	plurals map[string][]string
+	// pluralForm selects the plural form for the given count.
+	pluralForm func(n int) int
+}
+
+var catalogs = map[string]*Catalog{
+	"en": {
+		Language: "en",
+		messages: map[string]string{
+			"needs_review":           "Needs review",
+			"waiting_on_author":      "Waiting on author",
+			"ready_to_merge":         "Ready to merge",
+			"no_reviewer":            "No reviewer",
+			"author":                 "Author",
+			"created_at":             "Created at",
+			"opened":                 "Opened",
+			"approved_by":            "Approved by",
+			"none":                   "None",
+			"extra":                  "Extra",
+			"unresolved_discussions": "Has unresolved blocking discussions",
+			"ago":                    "%s ago",
+			"and_more":               "...and %d more",
+			"less_than_minute":       "less than a minute",
+			"date_layout":            "2 January 2006, 15:04 MST",
+			"report_title":           "Open merge requests",
+			"generated_at":           "Generated at",
+			"merge_request":          "Merge request",
+			"state":                  "State",
+			"reviewers":              "Reviewers",
+			"no_merge_requests":      "No opened merge requests found.",
+			"reviewer_away":          "Reviewer away (%s) — consider reassigning",
+			"author_away":            "Author away",
+			"suggested_reviewer":     "Suggested reviewer: %s",
+		},
+		plurals: map[string][]string{
+			"minute":       {"%d minute", "%d minutes"},
+			"hour":         {"%d hour", "%d hours"},
+			"day":          {"%d day", "%d days"},
+			"business_day": {"%d business day", "%d business days"},
+		},
+		pluralForm: func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+	},
+	"pl": {
+		Language: "pl",
+		messages: map[string]string{
+			"needs_review":           "Do przeglądu",
+			"waiting_on_author":      "Czeka na autora",
+			"ready_to_merge":         "Gotowe do scalenia",
+			"no_reviewer":            "Brak recenzenta",
+			"author":                 "Autor",
+			"created_at":             "Utworzono",
+			"opened":                 "Otwarto",
+			"approved_by":            "Zatwierdzone przez",
+			"none":                   "Brak",
+			"extra":                  "Uwagi",
+			"unresolved_discussions": "Ma nierozwiązane blokujące dyskusje",
+			"ago":                    "%s temu",
+			"and_more":               "...i %d więcej",
+			"less_than_minute":       "mniej niż minutę",
+			"date_layout":            "02.01.2006, 15:04 MST",
+			"report_title":           "Otwarte merge requesty",
+			"generated_at":           "Wygenerowano",
+			"merge_request":          "Merge request",
+			"state":                  "Status",
+			"reviewers":              "Recenzenci",
+			"no_merge_requests":      "Nie znaleziono otwartych merge requestów.",
+			"reviewer_away":          "Recenzent nieobecny (%s) — rozważ zmianę",
+			"author_away":            "Autor nieobecny",
+			"suggested_reviewer":     "Proponowany recenzent: %s",
+		},
+		plurals: map[string][]string{
+			"minute":       {"%d minutę", "%d minuty", "%d minut"},
+			"hour":         {"%d godzinę", "%d godziny", "%d godzin"},
+			"day":          {"%d dzień", "%d dni", "%d dni"},
+			"business_day": {"%d dzień roboczy", "%d dni robocze", "%d dni roboczych"},
+		},
+		pluralForm: func(n int) int {
+			switch {
+			case n == 1:
+				return 0
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return 1
+			default:
+				return 2
+			}
+		},
+	},
+}
+
+// catalogFor returns the catalog for the given language, English if it is empty.
+func catalogFor(language string) (*Catalog, error) {
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	catalog, ok := catalogs[language]
+	if !ok {
+		var languages []string
+		for lang := range catalogs {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+		return nil, fmt.Errorf("unsupported language %q, must be one of: %s", language, strings.Join(languages, ", "))
+	}
+
+	return catalog, nil
+}
+
+// T returns the translated message for key formatted with args.
+// Unknown keys are returned as is, so missing translations are easy to spot.
+func (c *Catalog) T(key string, args ...any) string {
+	format, ok := c.messages[key]
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// N returns the plural form of the message for key matching the count n.
+func (c *Catalog) N(key string, n int) string {
+	forms, ok := c.plurals[key]
+	if !ok {
+		return fmt.Sprintf("%d %s", n, key)
+	}
+	return fmt.Sprintf(forms[c.pluralForm(n)], n)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestCatalogFor(t *testing.T) {
+	catalog, err := catalogFor("")
+	require.NoError(t, err)
+	assert.Equal(t, "en", catalog.Language)
+
+	catalog, err = catalogFor("pl")
+	require.NoError(t, err)
+	assert.Equal(t, "pl", catalog.Language)
+
+	_, err = catalogFor("xx")
+	assert.ErrorContains(t, err, `unsupported language "xx", must be one of: en, pl`)
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	en := catalogs[defaultLanguage]
+
+	for lang, catalog := range catalogs {
+		for key := range en.messages {
+			assert.Contains(t, catalog.messages, key, "message %q is missing in %q", key, lang)
+		}
+		for key := range en.plurals {
+			assert.Contains(t, catalog.plurals, key, "plural %q is missing in %q", key, lang)
+		}
+	}
+}
+
+func TestCatalog_N(t *testing.T) {
+	en := catalogs["en"]
+	assert.Equal(t, "1 day", en.N("day", 1))
+	assert.Equal(t, "2 days", en.N("day", 2))
+	assert.Equal(t, "0 days", en.N("day", 0))
+
+	pl := catalogs["pl"]
+	assert.Equal(t, "1 dzień roboczy", pl.N("business_day", 1))
+	assert.Equal(t, "3 dni robocze", pl.N("business_day", 3))
+	assert.Equal(t, "5 dni roboczych", pl.N("business_day", 5))
+	assert.Equal(t, "12 godzin", pl.N("hour", 12))
+	assert.Equal(t, "22 godziny", pl.N("hour", 22))
+
+	assert.Equal(t, "3 weeks", en.N("weeks", 3), "unknown keys fall back to the key")
+}
+
+func TestFormatMergeRequestsSummary_Polish(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	now := createdAt.Add(3 * time.Hour)
+
+	mrs := []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				Title: "Poprawka", WebURL: "https://gitlab.com/mr/1", CreatedAt: &createdAt,
+				Author: &gitlab.BasicUser{Name: "Jan Kowalski"},
+			},
+		},
+	}
+
+	config := &Config{RelativeDates: true}
+	config.Slack.Language = "pl"
+
+	summary, err := formatMergeRequestsSummary(mrs, config, now)
+	require.NoError(t, err)
+
+	expected := "*Czeka na autora (1)*\n\n" +
+		":arrow_forward: <https://gitlab.com/mr/1|Poprawka>\n*Autor:* Jan Kowalski\n*Otwarto:* 3 godziny temu\n*Zatwierdzone przez:* Brak\n" +
+		"*Uwagi:* :warning: Ma nierozwiązane blokujące dyskusje\n\n"
+
+	assert.Equal(t, expected, summary)
+}
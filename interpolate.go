@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// interpolationRegexp matches "$$", an escaped dollar sign, and
+// "${VAR}" or "${VAR:-default}" references to environment variables.
+var interpolationRegexp = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolate replaces environment variable references in s. The default
+// value is used when the variable is unset or empty; a variable without a
+// default must be set.
+func interpolate(s string, env Env) (string, error) {
+	var missing []string
+
+	result := interpolationRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		m := interpolationRegexp.FindStringSubmatch(match)
+		if value := env.Getenv(m[1]); value != "" {
+			return value
+		}
+		if strings.Contains(match, ":-") {
+			return m[2]
+		}
+
+		missing = append(missing, m[1])
+		return ""
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// interpolateNode interpolates environment variables in all scalar values of
+// the document, leaving keys untouched. Interpolated values are retyped, so
+// "${PROJECT_ID}" can be decoded as a number.
+func interpolateNode(node *yaml.Node, env Env) configErrors {
+	var errs configErrors
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			errs = append(errs, interpolateNode(child, env)...)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			errs = append(errs, interpolateNode(node.Content[i], env)...)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			break
+		}
+
+		// On failure the value is cleared to not report it again as invalid.
+		value, err := interpolate(node.Value, env)
+		if err != nil {
+			errs = append(errs, configError{line: node.Line, message: err.Error()})
+		}
+
+		if value != node.Value {
+			node.Value = value
+			node.Tag = ""
+			node.Style &^= yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+		}
+	}
+
+	return errs
+}
+
+// secretField is a secret configuration value that can also be read from a file.
+type secretField struct {
+	path  string
+	value *string
+	file  string
+}
+
+func (c *Config) secretFields() []secretField {
+	return []secretField{
+		{"gitlab.token", &c.GitLab.Token, c.GitLab.TokenFile},
+		{"slack.webhook_url", &c.Slack.WebhookURL, c.Slack.WebhookURLFile},
+		{"email.password", &c.Email.Password, c.Email.PasswordFile},
+		{"discord.webhook_url", &c.Discord.WebhookURL, c.Discord.WebhookURLFile},
+		{"mattermost.webhook_url", &c.Mattermost.WebhookURL, c.Mattermost.WebhookURLFile},
+		{"google_chat.webhook_url", &c.GoogleChat.WebhookURL, c.GoogleChat.WebhookURLFile},
+		{"webhook.secret", &c.Webhook.Secret, c.Webhook.SecretFile},
+		{"telegram.bot_token", &c.Telegram.BotToken, c.Telegram.BotTokenFile},
+		{"matrix.access_token", &c.Matrix.AccessToken, c.Matrix.AccessTokenFile},
+	}
+}
+
+// resolveSecretFiles sets secret values from their *_file variants.
+func (v *configValidator) resolveSecretFiles(config *Config) {
+	for _, field := range config.secretFields() {
+		if field.file == "" {
+			continue
+		}
+
+		path := field.path + "_file"
+		if *field.value != "" {
+			v.errorf(path, "%s and %s are mutually exclusive", field.path, path)
+			continue
+		}
+
+		value, err := readSecretFile(field.file)
+		if err != nil {
+			v.errorf(path, "error reading %s: %v", path, err)
+			continue
+		}
+		*field.value = value
+	}
+}
+
+// getenvSecret returns the value of the environment variable key, or the
+// contents of the file named by key_FILE, following the convention of Docker images.
+func (v *configValidator) getenvSecret(env Env, key string) string {
+	value := env.Getenv(key)
+	file := env.Getenv(key + "_FILE")
+
+	if file == "" {
+		return value
+	}
+	if value != "" {
+		v.errorf("", "%s and %s_FILE environment variables are mutually exclusive", key, key)
+		return value
+	}
+
+	value, err := readSecretFile(file)
+	if err != nil {
+		v.errorf("", "error reading %s_FILE: %v", key, err)
+	}
+	return value
+}
+
+// readSecretFile returns the contents of a secret file without the trailing newline.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
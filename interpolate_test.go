@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	env := &MockEnv{values: map[string]string{"HOST": "gitlab.example.com", "EMPTY": ""}}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://${HOST}/api", "https://gitlab.example.com/api"},
+		{"${MISSING:-fallback}", "fallback"},
+		{"${EMPTY:-fallback}", "fallback"},
+		{"${HOST:-fallback}", "gitlab.example.com"},
+		{"${MISSING:-}", ""},
+		{"price: $$5, ${HOST}", "price: $5, gitlab.example.com"},
+		{"$HOST", "$HOST"},
+	}
+
+	for _, test := range tests {
+		actual, err := interpolate(test.input, env)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, actual, test.input)
+	}
+
+	_, err := interpolate("${MISSING}/${OTHER}", env)
+	assert.EqualError(t, err, "environment variable MISSING, OTHER is not set")
+}
+
+func TestLoadConfigInterpolation(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tokenFile := writeFile("gitlab-token", "secret-token\n")
+
+	t.Run("variables and secret files", func(t *testing.T) {
+		path := writeFile("config.yaml", `gitlab:
+  url: https://${GITLAB_HOST}
+  token_file: `+tokenFile+`
+slack:
+  webhook_url: ${SLACK_URL:-https://hooks.slack.com/services/default}
+projects:
+  - id: ${PROJECT_ID}
+  - id: "${OTHER_PROJECT_ID}"
+priority:
+  top: ${TOP:-5}
+`)
+
+		env := &MockEnv{values: map[string]string{
+			"CONFIG_PATH":      path,
+			"GITLAB_HOST":      "gitlab.example.com",
+			"PROJECT_ID":       "12",
+			"OTHER_PROJECT_ID": "34",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "https://gitlab.example.com", config.GitLab.URL)
+		assert.Equal(t, "secret-token", config.GitLab.Token)
+		assert.Equal(t, "https://hooks.slack.com/services/default", config.Slack.WebhookURL)
+		assert.Equal(t, []ConfigProject{{ID: 12}, {ID: 34}}, config.Projects)
+		assert.Equal(t, 5, config.Priority.Top)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		path := writeFile("config.yaml", `gitlab:
+  token: token
+  token_file: `+tokenFile+`
+slack:
+  webhook_url: ${SLACK_URL}
+  webhook_url_file: /nonexistent/slack
+projects:
+  - id: 1
+`)
+
+		env := &MockEnv{values: map[string]string{"CONFIG_PATH": path, "DRY_RUN": "true"}}
+
+		_, err := loadConfig(env)
+		assert.EqualError(t, err, `3 configuration errors:
+  - line 5: environment variable SLACK_URL is not set
+  - line 3: gitlab.token and gitlab.token_file are mutually exclusive
+  - line 6: error reading slack.webhook_url_file: open /nonexistent/slack: no such file or directory`)
+	})
+
+	t.Run("file environment variables", func(t *testing.T) {
+		env := &MockEnv{values: map[string]string{
+			"CONFIG_PATH":       "NONEXISTING.yaml",
+			"GITLAB_TOKEN_FILE": tokenFile,
+			"PROJECTS":          "1",
+			"DRY_RUN":           "true",
+		}}
+
+		config, err := loadConfig(env)
+		require.NoError(t, err)
+		assert.Equal(t, "secret-token", config.GitLab.Token)
+
+		env.values["GITLAB_TOKEN"] = "token"
+		_, err = loadConfig(env)
+		assert.EqualError(t, err, "GITLAB_TOKEN and GITLAB_TOKEN_FILE environment variables are mutually exclusive")
+	})
+}
@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Distroless and Lambda images may lack the timezone database.
 
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/reugn/go-quartz/job"
 	"github.com/reugn/go-quartz/quartz"
-	"github.com/xanzy/go-gitlab"
 )
 
 func main() {
@@ -24,20 +25,9 @@ func main() {
 
 // Entry point for normal execution as a standalone application.
 func mainStandalone() {
-	config, err := loadConfig(&OsEnv{})
-	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+	if err := runCommand(context.Background(), os.Args[1:], &OsEnv{}, os.Stdout); err != nil {
+		log.Fatal(err)
 	}
-
-	if config.CronSchedule == "" {
-		log.Printf("Running in one-shot mode")
-		if err := execute(config); err != nil {
-			log.Fatalf("Error executing: %v", err)
-		}
-		return
-	}
-
-	runScheduler(config)
 }
 
 // Entry point for AWS Lambda execution.
@@ -45,34 +35,51 @@ func mainLambda() {
 	lambda.Start(HandleRequest)
 }
 
-func runScheduler(config *Config) {
-	log.Printf("Running in cron mode with schedule: %s", config.CronSchedule)
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// runScheduler runs teams on their cron schedules until the process receives
+// SIGINT or SIGTERM. The configuration is reloaded when its file changes or on
+// SIGHUP. An error is returned if a run doesn't finish within the shutdown timeout.
+func runScheduler(ctx context.Context, config *Config, env Env) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	// Runs in progress are waited for on shutdown, so the scheduler is not
+	// stopped by the signal directly.
 	sched := quartz.NewStdScheduler()
-	sched.Start(ctx)
+	sched.Start(context.Background())
 
-	cronTrigger, err := quartz.NewCronTrigger(config.CronSchedule)
-	if err != nil {
-		log.Fatalf("Error creating cron trigger: %v", err)
+	s := newScheduler(sched, env)
+	if err := s.apply(config); err != nil {
+		sched.Stop()
+		return err
 	}
 
-	executeJob := job.NewFunctionJob(func(_ context.Context) (int, error) {
-		if err := execute(config); err != nil {
-			log.Printf("Error during scheduled execution: %v", err)
-			return 1, err // Indicate failure
+	path := configPath(env)
+	if _, err := os.Stat(path); err == nil {
+		if err := watchConfig(ctx, path, s.watchedFiles, s.reload); err != nil {
+			log.Printf("Configuration file changes will not be applied: %v", err)
 		}
-		return 0, nil // Indicate success
-	})
-
-	err = sched.ScheduleJob(quartz.NewJobDetail(executeJob, quartz.NewJobKey("executeJob")), cronTrigger)
-	if err != nil {
-		log.Fatalf("Error scheduling job: %v", err)
 	}
 
-	<-ctx.Done()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			log.Println("Received SIGHUP, reloading configuration.")
+			s.reload()
+		case <-ctx.Done():
+			// Restore the default behaviour, so that a second signal terminates immediately.
+			stop()
+			log.Println("Shutting down, waiting for runs in progress to finish.")
+			if err := s.shutdown(); err != nil {
+				return err
+			}
+			log.Println("Shutdown complete.")
+			return nil
+		}
+	}
 }
 
 // LambdaInput represents the input event for the Lambda function.
@@ -86,7 +93,7 @@ func HandleRequest(ctx context.Context, input LambdaInput) (string, error) {
 		return "", err
 	}
 
-	err = execute(config)
+	err = execute(ctx, config, &SystemClock{})
 	if err != nil {
 		return "", err
 	}
@@ -94,67 +101,116 @@ func HandleRequest(ctx context.Context, input LambdaInput) (string, error) {
 	return "Success", nil
 }
 
-func execute(config *Config) error {
-	glClient, err := gitlab.NewClient(config.GitLab.Token,
-		gitlab.WithBaseURL(config.GitLab.URL))
+// execute runs all teams once, within the run timeout if it is configured.
+func execute(ctx context.Context, config *Config, clock Clock) error {
+	gitlabClient, err := newGitLabClient(config)
 	if err != nil {
-		return fmt.Errorf("error creating GitLab client: %w", err)
+		return err
 	}
 
-	gitlabClient := &gitLabClient{client: glClient}
+	ctx, cancel := config.withRunTimeout(ctx)
+	defer cancel()
+
+	return executeTeams(ctx, config.teams(), gitlabClient, clock)
+}
+
+// executeTeams runs teams one after another, sharing GitLab responses between
+// them. It continues after a failure of a team and returns the first error,
+// but teams left are not run once ctx is done.
+func executeTeams(ctx context.Context, teams []*Config, client GitLabClient, clock Clock) error {
+	cache := newCachingGitLabClient(client)
+	now := clock.Now()
+
+	var firstErr error
+	for _, team := range teams {
+		if err := ctx.Err(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("run stopped before all teams were run: %w", err)
+			}
+			break
+		}
+
+		if team.team != "" {
+			log.Printf("Running team %s", team.team)
+		}
+
+		if err := executeTeam(ctx, team, cache, now); err != nil {
+			if team.team == "" {
+				return err
+			}
 
-	mrs, err := fetchOpenedMergeRequests(config, gitlabClient)
+			log.Printf("Error running team %s: %v", team.team, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error running team %s: %w", team.team, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// executeTeam collects merge requests of a single team and delivers them.
+func executeTeam(ctx context.Context, config *Config, client GitLabClient, now time.Time) error {
+	mrs, err := collectMergeRequests(ctx, config, client, now)
 	if err != nil {
-		return fmt.Errorf("error fetching opened merge requests: %w", err)
+		return err
 	}
 
-	mrs = filterMergeRequestsByAuthor(mrs, config.Authors)
+	if config.Export.Format != "" {
+		if err := exportMergeRequests(config, mrs, now); err != nil {
+			return fmt.Errorf("error exporting merge requests: %w", err)
+		}
+	}
+
+	if err := writeReports(config, mrs, now); err != nil {
+		return err
+	}
 
 	if len(mrs) == 0 {
 		log.Println("No opened merge requests found.")
-		return nil
 	}
 
-	summary := formatMergeRequestsSummary(mrs)
+	return sendToDestinations(ctx, newDestinations(config, client), mrs, now)
+}
 
-	slackClient := &slackClient{webhookURL: config.Slack.WebhookURL}
-	err = sendSlackMessage(slackClient, summary)
-	if err != nil {
-		return fmt.Errorf("error sending Slack message: %w", err)
+// teamNames returns names of the teams in order.
+func teamNames(teams []*Config) []string {
+	names := make([]string, len(teams))
+	for i, team := range teams {
+		names[i] = team.team
 	}
-
-	log.Println("Successfully sent merge request summary to Slack.")
-	return nil
+	return names
 }
 
-func formatMergeRequestsSummary(mrs []*MergeRequestWithApprovals) string {
-	var summary string
-	for _, mr := range mrs {
-		approvedBy := strings.Join(mr.ApprovedBy, ", ")
-		if approvedBy == "" {
-			approvedBy = "None"
-		}
+// collectMergeRequests fetches opened merge requests and prepares them for
+// rendering: filtered by author, scored and sorted.
+func collectMergeRequests(ctx context.Context, config *Config, client GitLabClient, now time.Time) ([]*MergeRequestWithApprovals, error) {
+	mrs, err := fetchOpenedMergeRequests(ctx, config, client)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching opened merge requests: %w", err)
+	}
 
-		createdAtStr := mr.MergeRequest.CreatedAt.Format("2 January 2006, 15:04 MST")
+	mrs = filterMergeRequestsByAuthor(mrs, config.Authors)
 
-		var extra string
-		if !mr.MergeRequest.BlockingDiscussionsResolved {
-			extra = ":warning: Has unresolved blocking discussions"
-		}
+	if err := markAwayUsers(ctx, config, client, mrs, now); err != nil {
+		return nil, fmt.Errorf("error checking out-of-office users: %w", err)
+	}
 
-		summary += fmt.Sprintf(
-			":arrow_forward: <%s|%s>\n*Author:* %s\n*Created at:* %s\n*Approved by:* %s\n",
-			mr.MergeRequest.WebURL, mr.MergeRequest.Title, mr.MergeRequest.Author.Name, createdAtStr, approvedBy,
-		)
+	scoreMergeRequests(mrs, config.Priority.Weights, now)
+	return sortMergeRequests(mrs, config.SortBy), nil
+}
 
-		if extra != "" {
-			summary += fmt.Sprintf("*Extra:* %s\n", extra)
-		}
+func formatMergeRequestsSummary(mrs []*MergeRequestWithApprovals, config *Config, now time.Time) (string, error) {
+	tmpl := config.summaryTemplate
+	if tmpl == nil {
+		tmpl = defaultTemplate
+	}
 
-		summary += "\n"
+	catalog, err := catalogFor(config.Slack.Language)
+	if err != nil {
+		return "", err
 	}
 
-	return summary
+	return renderSummaryTemplate(tmpl, buildSummary(mrs, config, catalog, now))
 }
 
 func filterMergeRequestsByAuthor(mrs []*MergeRequestWithApprovals, authors []ConfigAuthor) []*MergeRequestWithApprovals {
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/flexoid/mergentle-reminder/mocks"
 	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xanzy/go-gitlab"
 )
@@ -98,3 +106,70 @@ func TestFilterMergeRequestsByAuthor_OptionalAuthors(t *testing.T) {
 	filteredMRs := filterMergeRequestsByAuthor(mrs, authors)
 	require.Equal(t, 1, len(filteredMRs))
 }
+
+func TestExecuteTeams(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	exportPath := filepath.Join(t.TempDir(), "export.json")
+
+	client := mocks.NewGitLabClient(t)
+
+	// Project 1 is shared by both teams, but fetched once.
+	for _, projectID := range []int{1, 2} {
+		client.EXPECT().ListProjectMergeRequests(mock.Anything, projectID, mock.Anything).Return(
+			[]*gitlab.MergeRequest{{
+				IID: projectID, ProjectID: projectID, CreatedAt: &createdAt,
+				Author: &gitlab.BasicUser{ID: projectID, Username: fmt.Sprintf("user%d", projectID)},
+			}},
+			&gitlab.Response{CurrentPage: 1, TotalPages: 1},
+			nil,
+		).Once()
+		client.EXPECT().GetMergeRequestApprovalsConfiguration(mock.Anything, projectID, projectID).Return(
+			&gitlab.MergeRequestApprovals{}, &gitlab.Response{}, nil,
+		).Once()
+	}
+
+	backend := &Config{
+		team:     "backend",
+		Projects: []ConfigProject{{ID: 1}, {ID: 2}},
+		Authors:  []ConfigAuthor{{Username: "user2"}},
+		Export:   ConfigExport{Format: ExportFormatJSON, Path: exportPath},
+	}
+	frontend := &Config{
+		team:     "frontend",
+		Projects: []ConfigProject{{ID: 1}},
+		Reports:  []ConfigReport{{Format: ReportFormatMarkdown, Path: filepath.Join(t.TempDir(), "missing", "report.md")}},
+	}
+
+	err := executeTeams(context.Background(), []*Config{frontend, backend}, client, &fixedClock{now: createdAt})
+	assert.ErrorContains(t, err, "error running team frontend: error writing markdown report")
+
+	// The backend team ran despite the failure of the frontend team.
+	data, err := os.ReadFile(exportPath)
+	require.NoError(t, err)
+
+	var export Export
+	require.NoError(t, json.Unmarshal(data, &export))
+	require.Len(t, export.MergeRequests, 1)
+	assert.Equal(t, "user2", export.MergeRequests[0].Author)
+}
+
+func TestExecuteTeams_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := mocks.NewGitLabClient(t)
+	client.EXPECT().ListProjectMergeRequests(mock.Anything, 1, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ int, _ *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+			cancel()
+			return nil, nil, ctx.Err()
+		},
+	).Once()
+
+	backend := &Config{team: "backend", Projects: []ConfigProject{{ID: 1}}}
+	// The mock fails the test if merge requests of the frontend team are fetched.
+	frontend := &Config{team: "frontend", Projects: []ConfigProject{{ID: 2}}}
+
+	err := executeTeams(ctx, []*Config{backend, frontend}, client, &fixedClock{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "error running team backend")
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// matrixMaxContentBytes limits the JSON encoded bodies of an event, keeping
+// it below the 65536 bytes limit of the Matrix specification, which also
+// counts the rest of the content and the metadata added by the homeserver.
+const matrixMaxContentBytes = 60000
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// matrixBlock is a part of a message in both plain text and HTML.
+type matrixBlock struct {
+	text string
+	html string
+}
+
+// size returns the size of the block in both bodies of an event, as encoded
+// by json.Marshal, which escapes <, > and & in HTML to six bytes each.
+func (b matrixBlock) size() int {
+	return jsonStringSize(b.text) + jsonStringSize(b.html)
+}
+
+// jsonStringSize returns the length of s encoded as a JSON string, without quotes.
+func jsonStringSize(s string) int {
+	data, _ := json.Marshal(s)
+	return len(data) - 2
+}
+
+// matrixDestination sends the summary as m.room.message events with the
+// Matrix client-server API, split into as many events as needed.
+type matrixDestination struct {
+	config *Config
+	client *http.Client
+}
+
+func newMatrixDestination(config *Config) *matrixDestination {
+	return &matrixDestination{config: config, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (d *matrixDestination) Name() string {
+	return "Matrix"
+}
+
+func (d *matrixDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	cfg := d.config.Matrix
+
+	catalog, err := catalogFor(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	blocks := matrixBlocks(buildSummary(mrs, d.config, catalog, now))
+
+	sizes := make([]int, len(blocks))
+	for i, block := range blocks {
+		sizes[i] = block.size()
+	}
+
+	for i, chunk := range splitBlocks(sizes, matrixMaxContentBytes) {
+		msg := &matrixMessage{MsgType: "m.text", Format: "org.matrix.custom.html"}
+		for _, block := range blocks[chunk[0]:chunk[1]] {
+			msg.Body += block.text
+			msg.FormattedBody += block.html
+		}
+		msg.Body = strings.TrimSuffix(msg.Body, "\n")
+
+		if d.config.DryRun {
+			if err := writeDryRunPayload(d.config.DryRunOutput, msg); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The transaction ID makes retried requests idempotent.
+		txnID := fmt.Sprintf("mergentle-%d-%d", now.UnixNano(), i)
+		if err := d.sendEvent(ctx, txnID, msg); err != nil {
+			return fmt.Errorf("error sending Matrix message: %w", err)
+		}
+	}
+
+	if !d.config.DryRun {
+		log.Println("Successfully sent merge request summary to Matrix.")
+	}
+	return nil
+}
+
+func (d *matrixDestination) sendEvent(ctx context.Context, txnID string, msg *matrixMessage) error {
+	cfg := d.config.Matrix
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error encoding payload: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(cfg.HomeserverURL, "/"), url.PathEscape(cfg.RoomID), url.PathEscape(txnID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+cfg.AccessToken)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// matrixBlocks renders the summary as blocks, one per merge request, with
+// section and group headers prepended to the first merge request below them.
+// Titles are shortened when a block would not fit in a single event.
+func matrixBlocks(summary *Summary) []matrixBlock {
+	var blocks []matrixBlock
+
+	for _, section := range summary.Sections {
+		title := fmt.Sprintf("%s (%d)", section.Title, section.Count)
+		header := matrixBlock{
+			text: title + "\n",
+			html: "<h4>" + html.EscapeString(title) + "</h4>",
+		}
+
+		for _, group := range section.Groups {
+			if group.Name != "" {
+				header.text += group.Name + "\n"
+				header.html += "<p><em>" + html.EscapeString(group.Name) + "</em></p>"
+			}
+
+			for _, mr := range group.MergeRequests {
+				details := mergeRequestDetails(summary, mr)
+
+				escaped := make([]string, len(details))
+				for i, detail := range details {
+					escaped[i] = html.EscapeString(detail)
+				}
+
+				render := func(title string) matrixBlock {
+					return matrixBlock{
+						text: fmt.Sprintf("%s!%d %s (%s)\n%s\n", header.text, mr.IID, title, mr.URL, strings.Join(details, "\n")),
+						html: fmt.Sprintf(`%s<p><a href="%s">!%d %s</a><br>%s</p>`,
+							header.html, html.EscapeString(mr.URL), mr.IID, html.EscapeString(title), strings.Join(escaped, "<br>")),
+					}
+				}
+
+				blocks = append(blocks, fitTitle(mr.Title, matrixMaxContentBytes, matrixBlock.size, render))
+				header = matrixBlock{}
+			}
+		}
+	}
+
+	if summary.Omitted > 0 {
+		text := summary.catalog.T("and_more", summary.Omitted)
+		block := matrixBlock{text: text, html: "<p>" + html.EscapeString(text) + "</p>"}
+		if summary.MoreURL != "" {
+			block.text = fmt.Sprintf("%s (%s)", text, summary.MoreURL)
+			block.html = fmt.Sprintf(`<p><a href="%s">%s</a></p>`, html.EscapeString(summary.MoreURL), html.EscapeString(text))
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
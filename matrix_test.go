@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrixDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	var messages []matrixMessage
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.EscapedPath())
+
+		var msg matrixMessage
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &msg))
+		messages = append(messages, msg)
+
+		io.WriteString(w, `{"event_id": "$1"}`)
+	}))
+	defer server.Close()
+
+	config := &Config{Matrix: ConfigMatrix{HomeserverURL: server.URL + "/", AccessToken: "token", RoomID: "!room:example.com"}}
+
+	t.Run("single message", func(t *testing.T) {
+		messages, paths = nil, nil
+		require.NoError(t, newMatrixDestination(config).Send(context.Background(), testMergeRequestsForExport(), now))
+
+		require.Len(t, messages, 1)
+		assert.Equal(t, "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/mergentle-1705233600000000000-0", paths[0])
+		assert.Equal(t, "m.text", messages[0].MsgType)
+		assert.Equal(t, "org.matrix.custom.html", messages[0].Format)
+		assert.True(t, strings.HasPrefix(messages[0].Body,
+			"Needs review (1)\n!12 Add \"export\", finally (https://gitlab.com/group/alpha/-/merge_requests/12)\nAuthor: John Doe\n"))
+		assert.True(t, strings.HasPrefix(messages[0].FormattedBody,
+			`<h4>Needs review (1)</h4><p><a href="https://gitlab.com/group/alpha/-/merge_requests/12">!12 Add &#34;export&#34;, finally</a><br>Author: John Doe<br>`))
+	})
+
+	t.Run("splits long summary", func(t *testing.T) {
+		messages, paths = nil, nil
+		require.NoError(t, newMatrixDestination(config).Send(context.Background(), manyMergeRequests(600), now))
+
+		require.Greater(t, len(messages), 1)
+		var body string
+		for _, msg := range messages {
+			data, err := json.Marshal(msg)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(data), 65536)
+			body += msg.Body
+		}
+		assert.Equal(t, 600, strings.Count(body, "Author: John Doe"))
+		assert.NotEqual(t, paths[0], paths[1])
+	})
+
+	t.Run("shortens block over the limit", func(t *testing.T) {
+		messages, paths = nil, nil
+		mrs := manyMergeRequests(1)
+		mrs[0].MergeRequest.Title = strings.Repeat("<b>", 5000)
+		require.NoError(t, newMatrixDestination(config).Send(context.Background(), mrs, now))
+
+		require.Len(t, messages, 1)
+		data, err := json.Marshal(messages[0])
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), 65536)
+		assert.Contains(t, messages[0].FormattedBody, "…</a><br>Author: John Doe")
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"errcode": "M_FORBIDDEN"}`, http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		config := &Config{Matrix: ConfigMatrix{HomeserverURL: server.URL, AccessToken: "token", RoomID: "!room:example.com"}}
+		err := newMatrixDestination(config).Send(context.Background(), testMergeRequestsForExport(), now)
+		assert.ErrorContains(t, err, "error sending Matrix message: unexpected status 403 Forbidden")
+	})
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	mock "github.com/stretchr/testify/mock"
 	gitlab "github.com/xanzy/go-gitlab"
 )
@@ -20,34 +21,296 @@ func (_m *GitLabClient) EXPECT() *GitLabClient_Expecter {
 	return &GitLabClient_Expecter{mock: &_m.Mock}
 }
 
-// GetMergeRequestApprovalsConfiguration provides a mock function with given fields: projectID, mergeRequestID
-func (_m *GitLabClient) GetMergeRequestApprovalsConfiguration(projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
-	ret := _m.Called(projectID, mergeRequestID)
+// CreateIssue provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) CreateIssue(ctx context.Context, projectID int, options *gitlab.CreateIssueOptions) (*gitlab.Issue, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
+
+	var r0 *gitlab.Issue
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.CreateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.CreateIssueOptions) *gitlab.Issue); ok {
+		r0 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Issue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.CreateIssueOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.CreateIssueOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_CreateIssue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIssue'
+type GitLabClient_CreateIssue_Call struct {
+	*mock.Call
+}
+
+// CreateIssue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - options *gitlab.CreateIssueOptions
+func (_e *GitLabClient_Expecter) CreateIssue(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_CreateIssue_Call {
+	return &GitLabClient_CreateIssue_Call{Call: _e.mock.On("CreateIssue", ctx, projectID, options)}
+}
+
+func (_c *GitLabClient_CreateIssue_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.CreateIssueOptions)) *GitLabClient_CreateIssue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.CreateIssueOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_CreateIssue_Call) Return(_a0 *gitlab.Issue, _a1 *gitlab.Response, _a2 error) *GitLabClient_CreateIssue_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_CreateIssue_Call) RunAndReturn(run func(context.Context, int, *gitlab.CreateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)) *GitLabClient_CreateIssue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWikiPage provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) CreateWikiPage(ctx context.Context, projectID int, options *gitlab.CreateWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
+
+	var r0 *gitlab.Wiki
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.CreateWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.CreateWikiPageOptions) *gitlab.Wiki); ok {
+		r0 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Wiki)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.CreateWikiPageOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.CreateWikiPageOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_CreateWikiPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWikiPage'
+type GitLabClient_CreateWikiPage_Call struct {
+	*mock.Call
+}
+
+// CreateWikiPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - options *gitlab.CreateWikiPageOptions
+func (_e *GitLabClient_Expecter) CreateWikiPage(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_CreateWikiPage_Call {
+	return &GitLabClient_CreateWikiPage_Call{Call: _e.mock.On("CreateWikiPage", ctx, projectID, options)}
+}
+
+func (_c *GitLabClient_CreateWikiPage_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.CreateWikiPageOptions)) *GitLabClient_CreateWikiPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.CreateWikiPageOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_CreateWikiPage_Call) Return(_a0 *gitlab.Wiki, _a1 *gitlab.Response, _a2 error) *GitLabClient_CreateWikiPage_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_CreateWikiPage_Call) RunAndReturn(run func(context.Context, int, *gitlab.CreateWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)) *GitLabClient_CreateWikiPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EditWikiPage provides a mock function with given fields: ctx, projectID, slug, options
+func (_m *GitLabClient) EditWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.EditWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, slug, options)
+
+	var r0 *gitlab.Wiki
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *gitlab.EditWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, slug, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *gitlab.EditWikiPageOptions) *gitlab.Wiki); ok {
+		r0 = rf(ctx, projectID, slug, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Wiki)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, *gitlab.EditWikiPageOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, slug, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string, *gitlab.EditWikiPageOptions) error); ok {
+		r2 = rf(ctx, projectID, slug, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_EditWikiPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditWikiPage'
+type GitLabClient_EditWikiPage_Call struct {
+	*mock.Call
+}
+
+// EditWikiPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - slug string
+//   - options *gitlab.EditWikiPageOptions
+func (_e *GitLabClient_Expecter) EditWikiPage(ctx interface{}, projectID interface{}, slug interface{}, options interface{}) *GitLabClient_EditWikiPage_Call {
+	return &GitLabClient_EditWikiPage_Call{Call: _e.mock.On("EditWikiPage", ctx, projectID, slug, options)}
+}
+
+func (_c *GitLabClient_EditWikiPage_Call) Run(run func(ctx context.Context, projectID int, slug string, options *gitlab.EditWikiPageOptions)) *GitLabClient_EditWikiPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*gitlab.EditWikiPageOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_EditWikiPage_Call) Return(_a0 *gitlab.Wiki, _a1 *gitlab.Response, _a2 error) *GitLabClient_EditWikiPage_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_EditWikiPage_Call) RunAndReturn(run func(context.Context, int, string, *gitlab.EditWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)) *GitLabClient_EditWikiPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMergeRequest provides a mock function with given fields: ctx, projectID, mergeRequestID, options
+func (_m *GitLabClient) GetMergeRequest(ctx context.Context, projectID int, mergeRequestID int, options *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, mergeRequestID, options)
+
+	var r0 *gitlab.MergeRequest
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, mergeRequestID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *gitlab.GetMergeRequestsOptions) *gitlab.MergeRequest); ok {
+		r0 = rf(ctx, projectID, mergeRequestID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.MergeRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *gitlab.GetMergeRequestsOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, mergeRequestID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, *gitlab.GetMergeRequestsOptions) error); ok {
+		r2 = rf(ctx, projectID, mergeRequestID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_GetMergeRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMergeRequest'
+type GitLabClient_GetMergeRequest_Call struct {
+	*mock.Call
+}
+
+// GetMergeRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - mergeRequestID int
+//   - options *gitlab.GetMergeRequestsOptions
+func (_e *GitLabClient_Expecter) GetMergeRequest(ctx interface{}, projectID interface{}, mergeRequestID interface{}, options interface{}) *GitLabClient_GetMergeRequest_Call {
+	return &GitLabClient_GetMergeRequest_Call{Call: _e.mock.On("GetMergeRequest", ctx, projectID, mergeRequestID, options)}
+}
+
+func (_c *GitLabClient_GetMergeRequest_Call) Run(run func(ctx context.Context, projectID int, mergeRequestID int, options *gitlab.GetMergeRequestsOptions)) *GitLabClient_GetMergeRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*gitlab.GetMergeRequestsOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_GetMergeRequest_Call) Return(_a0 *gitlab.MergeRequest, _a1 *gitlab.Response, _a2 error) *GitLabClient_GetMergeRequest_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_GetMergeRequest_Call) RunAndReturn(run func(context.Context, int, int, *gitlab.GetMergeRequestsOptions) (*gitlab.MergeRequest, *gitlab.Response, error)) *GitLabClient_GetMergeRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMergeRequestApprovalsConfiguration provides a mock function with given fields: ctx, projectID, mergeRequestID
+func (_m *GitLabClient) GetMergeRequestApprovalsConfiguration(ctx context.Context, projectID int, mergeRequestID int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, mergeRequestID)
 
 	var r0 *gitlab.MergeRequestApprovals
 	var r1 *gitlab.Response
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int, int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)); ok {
-		return rf(projectID, mergeRequestID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, mergeRequestID)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) *gitlab.MergeRequestApprovals); ok {
-		r0 = rf(projectID, mergeRequestID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *gitlab.MergeRequestApprovals); ok {
+		r0 = rf(ctx, projectID, mergeRequestID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*gitlab.MergeRequestApprovals)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) *gitlab.Response); ok {
-		r1 = rf(projectID, mergeRequestID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, mergeRequestID)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*gitlab.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(int, int) error); ok {
-		r2 = rf(projectID, mergeRequestID)
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, projectID, mergeRequestID)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -61,15 +324,16 @@ type GitLabClient_GetMergeRequestApprovalsConfiguration_Call struct {
 }
 
 // GetMergeRequestApprovalsConfiguration is a helper method to define mock.On call
+//   - ctx context.Context
 //   - projectID int
 //   - mergeRequestID int
-func (_e *GitLabClient_Expecter) GetMergeRequestApprovalsConfiguration(projectID interface{}, mergeRequestID interface{}) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
-	return &GitLabClient_GetMergeRequestApprovalsConfiguration_Call{Call: _e.mock.On("GetMergeRequestApprovalsConfiguration", projectID, mergeRequestID)}
+func (_e *GitLabClient_Expecter) GetMergeRequestApprovalsConfiguration(ctx interface{}, projectID interface{}, mergeRequestID interface{}) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
+	return &GitLabClient_GetMergeRequestApprovalsConfiguration_Call{Call: _e.mock.On("GetMergeRequestApprovalsConfiguration", ctx, projectID, mergeRequestID)}
 }
 
-func (_c *GitLabClient_GetMergeRequestApprovalsConfiguration_Call) Run(run func(projectID int, mergeRequestID int)) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
+func (_c *GitLabClient_GetMergeRequestApprovalsConfiguration_Call) Run(run func(ctx context.Context, projectID int, mergeRequestID int)) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
 	})
 	return _c
 }
@@ -79,39 +343,249 @@ func (_c *GitLabClient_GetMergeRequestApprovalsConfiguration_Call) Return(_a0 *g
 	return _c
 }
 
-func (_c *GitLabClient_GetMergeRequestApprovalsConfiguration_Call) RunAndReturn(run func(int, int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
+func (_c *GitLabClient_GetMergeRequestApprovalsConfiguration_Call) RunAndReturn(run func(context.Context, int, int) (*gitlab.MergeRequestApprovals, *gitlab.Response, error)) *GitLabClient_GetMergeRequestApprovalsConfiguration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProject provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) GetProject(ctx context.Context, projectID int, options *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
+
+	var r0 *gitlab.Project
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.GetProjectOptions) *gitlab.Project); ok {
+		r0 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.GetProjectOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.GetProjectOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_GetProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProject'
+type GitLabClient_GetProject_Call struct {
+	*mock.Call
+}
+
+// GetProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - options *gitlab.GetProjectOptions
+func (_e *GitLabClient_Expecter) GetProject(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_GetProject_Call {
+	return &GitLabClient_GetProject_Call{Call: _e.mock.On("GetProject", ctx, projectID, options)}
+}
+
+func (_c *GitLabClient_GetProject_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.GetProjectOptions)) *GitLabClient_GetProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.GetProjectOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_GetProject_Call) Return(_a0 *gitlab.Project, _a1 *gitlab.Response, _a2 error) *GitLabClient_GetProject_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_GetProject_Call) RunAndReturn(run func(context.Context, int, *gitlab.GetProjectOptions) (*gitlab.Project, *gitlab.Response, error)) *GitLabClient_GetProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserStatus provides a mock function with given fields: ctx, userID, options
+func (_m *GitLabClient) GetUserStatus(ctx context.Context, userID int, options ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error) {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gitlab.UserStatus
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error)); ok {
+		return rf(ctx, userID, options...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, ...gitlab.RequestOptionFunc) *gitlab.UserStatus); ok {
+		r0 = rf(ctx, userID, options...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.UserStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, ...gitlab.RequestOptionFunc) *gitlab.Response); ok {
+		r1 = rf(ctx, userID, options...)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, ...gitlab.RequestOptionFunc) error); ok {
+		r2 = rf(ctx, userID, options...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_GetUserStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStatus'
+type GitLabClient_GetUserStatus_Call struct {
+	*mock.Call
+}
+
+// GetUserStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - options ...gitlab.RequestOptionFunc
+func (_e *GitLabClient_Expecter) GetUserStatus(ctx interface{}, userID interface{}, options ...interface{}) *GitLabClient_GetUserStatus_Call {
+	return &GitLabClient_GetUserStatus_Call{Call: _e.mock.On("GetUserStatus",
+		append([]interface{}{ctx, userID}, options...)...)}
+}
+
+func (_c *GitLabClient_GetUserStatus_Call) Run(run func(ctx context.Context, userID int, options ...gitlab.RequestOptionFunc)) *GitLabClient_GetUserStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]gitlab.RequestOptionFunc, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(gitlab.RequestOptionFunc)
+			}
+		}
+		run(args[0].(context.Context), args[1].(int), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *GitLabClient_GetUserStatus_Call) Return(_a0 *gitlab.UserStatus, _a1 *gitlab.Response, _a2 error) *GitLabClient_GetUserStatus_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_GetUserStatus_Call) RunAndReturn(run func(context.Context, int, ...gitlab.RequestOptionFunc) (*gitlab.UserStatus, *gitlab.Response, error)) *GitLabClient_GetUserStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListGroupProjects provides a mock function with given fields: groupID, options
-func (_m *GitLabClient) ListGroupProjects(groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
-	ret := _m.Called(groupID, options)
+// GetWikiPage provides a mock function with given fields: ctx, projectID, slug, options
+func (_m *GitLabClient) GetWikiPage(ctx context.Context, projectID int, slug string, options *gitlab.GetWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, slug, options)
+
+	var r0 *gitlab.Wiki
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *gitlab.GetWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, slug, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, *gitlab.GetWikiPageOptions) *gitlab.Wiki); ok {
+		r0 = rf(ctx, projectID, slug, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Wiki)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, *gitlab.GetWikiPageOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, slug, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string, *gitlab.GetWikiPageOptions) error); ok {
+		r2 = rf(ctx, projectID, slug, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_GetWikiPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWikiPage'
+type GitLabClient_GetWikiPage_Call struct {
+	*mock.Call
+}
+
+// GetWikiPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - slug string
+//   - options *gitlab.GetWikiPageOptions
+func (_e *GitLabClient_Expecter) GetWikiPage(ctx interface{}, projectID interface{}, slug interface{}, options interface{}) *GitLabClient_GetWikiPage_Call {
+	return &GitLabClient_GetWikiPage_Call{Call: _e.mock.On("GetWikiPage", ctx, projectID, slug, options)}
+}
+
+func (_c *GitLabClient_GetWikiPage_Call) Run(run func(ctx context.Context, projectID int, slug string, options *gitlab.GetWikiPageOptions)) *GitLabClient_GetWikiPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(*gitlab.GetWikiPageOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_GetWikiPage_Call) Return(_a0 *gitlab.Wiki, _a1 *gitlab.Response, _a2 error) *GitLabClient_GetWikiPage_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_GetWikiPage_Call) RunAndReturn(run func(context.Context, int, string, *gitlab.GetWikiPageOptions) (*gitlab.Wiki, *gitlab.Response, error)) *GitLabClient_GetWikiPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListGroupProjects provides a mock function with given fields: ctx, groupID, options
+func (_m *GitLabClient) ListGroupProjects(ctx context.Context, groupID int, options *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+	ret := _m.Called(ctx, groupID, options)
 
 	var r0 []*gitlab.Project
 	var r1 *gitlab.Response
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)); ok {
-		return rf(groupID, options)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)); ok {
+		return rf(ctx, groupID, options)
 	}
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListGroupProjectsOptions) []*gitlab.Project); ok {
-		r0 = rf(groupID, options)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListGroupProjectsOptions) []*gitlab.Project); ok {
+		r0 = rf(ctx, groupID, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*gitlab.Project)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, *gitlab.ListGroupProjectsOptions) *gitlab.Response); ok {
-		r1 = rf(groupID, options)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.ListGroupProjectsOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, groupID, options)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*gitlab.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(int, *gitlab.ListGroupProjectsOptions) error); ok {
-		r2 = rf(groupID, options)
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.ListGroupProjectsOptions) error); ok {
+		r2 = rf(ctx, groupID, options)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -125,15 +599,16 @@ type GitLabClient_ListGroupProjects_Call struct {
 }
 
 // ListGroupProjects is a helper method to define mock.On call
+//   - ctx context.Context
 //   - groupID int
 //   - options *gitlab.ListGroupProjectsOptions
-func (_e *GitLabClient_Expecter) ListGroupProjects(groupID interface{}, options interface{}) *GitLabClient_ListGroupProjects_Call {
-	return &GitLabClient_ListGroupProjects_Call{Call: _e.mock.On("ListGroupProjects", groupID, options)}
+func (_e *GitLabClient_Expecter) ListGroupProjects(ctx interface{}, groupID interface{}, options interface{}) *GitLabClient_ListGroupProjects_Call {
+	return &GitLabClient_ListGroupProjects_Call{Call: _e.mock.On("ListGroupProjects", ctx, groupID, options)}
 }
 
-func (_c *GitLabClient_ListGroupProjects_Call) Run(run func(groupID int, options *gitlab.ListGroupProjectsOptions)) *GitLabClient_ListGroupProjects_Call {
+func (_c *GitLabClient_ListGroupProjects_Call) Run(run func(ctx context.Context, groupID int, options *gitlab.ListGroupProjectsOptions)) *GitLabClient_ListGroupProjects_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int), args[1].(*gitlab.ListGroupProjectsOptions))
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.ListGroupProjectsOptions))
 	})
 	return _c
 }
@@ -143,39 +618,104 @@ func (_c *GitLabClient_ListGroupProjects_Call) Return(_a0 []*gitlab.Project, _a1
 	return _c
 }
 
-func (_c *GitLabClient_ListGroupProjects_Call) RunAndReturn(run func(int, *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)) *GitLabClient_ListGroupProjects_Call {
+func (_c *GitLabClient_ListGroupProjects_Call) RunAndReturn(run func(context.Context, int, *gitlab.ListGroupProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)) *GitLabClient_ListGroupProjects_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListProjectMergeRequests provides a mock function with given fields: projectID, options
-func (_m *GitLabClient) ListProjectMergeRequests(projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
-	ret := _m.Called(projectID, options)
+// ListProjectIssues provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) ListProjectIssues(ctx context.Context, projectID int, options *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
+
+	var r0 []*gitlab.Issue
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListProjectIssuesOptions) []*gitlab.Issue); ok {
+		r0 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gitlab.Issue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.ListProjectIssuesOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.ListProjectIssuesOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_ListProjectIssues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListProjectIssues'
+type GitLabClient_ListProjectIssues_Call struct {
+	*mock.Call
+}
+
+// ListProjectIssues is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - options *gitlab.ListProjectIssuesOptions
+func (_e *GitLabClient_Expecter) ListProjectIssues(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_ListProjectIssues_Call {
+	return &GitLabClient_ListProjectIssues_Call{Call: _e.mock.On("ListProjectIssues", ctx, projectID, options)}
+}
+
+func (_c *GitLabClient_ListProjectIssues_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.ListProjectIssuesOptions)) *GitLabClient_ListProjectIssues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.ListProjectIssuesOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_ListProjectIssues_Call) Return(_a0 []*gitlab.Issue, _a1 *gitlab.Response, _a2 error) *GitLabClient_ListProjectIssues_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_ListProjectIssues_Call) RunAndReturn(run func(context.Context, int, *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, *gitlab.Response, error)) *GitLabClient_ListProjectIssues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListProjectMergeRequests provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) ListProjectMergeRequests(ctx context.Context, projectID int, options *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
 
 	var r0 []*gitlab.MergeRequest
 	var r1 *gitlab.Response
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)); ok {
-		return rf(projectID, options)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
 	}
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListProjectMergeRequestsOptions) []*gitlab.MergeRequest); ok {
-		r0 = rf(projectID, options)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListProjectMergeRequestsOptions) []*gitlab.MergeRequest); ok {
+		r0 = rf(ctx, projectID, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*gitlab.MergeRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, *gitlab.ListProjectMergeRequestsOptions) *gitlab.Response); ok {
-		r1 = rf(projectID, options)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.ListProjectMergeRequestsOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*gitlab.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(int, *gitlab.ListProjectMergeRequestsOptions) error); ok {
-		r2 = rf(projectID, options)
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.ListProjectMergeRequestsOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -189,15 +729,16 @@ type GitLabClient_ListProjectMergeRequests_Call struct {
 }
 
 // ListProjectMergeRequests is a helper method to define mock.On call
+//   - ctx context.Context
 //   - projectID int
 //   - options *gitlab.ListProjectMergeRequestsOptions
-func (_e *GitLabClient_Expecter) ListProjectMergeRequests(projectID interface{}, options interface{}) *GitLabClient_ListProjectMergeRequests_Call {
-	return &GitLabClient_ListProjectMergeRequests_Call{Call: _e.mock.On("ListProjectMergeRequests", projectID, options)}
+func (_e *GitLabClient_Expecter) ListProjectMergeRequests(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_ListProjectMergeRequests_Call {
+	return &GitLabClient_ListProjectMergeRequests_Call{Call: _e.mock.On("ListProjectMergeRequests", ctx, projectID, options)}
 }
 
-func (_c *GitLabClient_ListProjectMergeRequests_Call) Run(run func(projectID int, options *gitlab.ListProjectMergeRequestsOptions)) *GitLabClient_ListProjectMergeRequests_Call {
+func (_c *GitLabClient_ListProjectMergeRequests_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.ListProjectMergeRequestsOptions)) *GitLabClient_ListProjectMergeRequests_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int), args[1].(*gitlab.ListProjectMergeRequestsOptions))
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.ListProjectMergeRequestsOptions))
 	})
 	return _c
 }
@@ -207,46 +748,46 @@ func (_c *GitLabClient_ListProjectMergeRequests_Call) Return(_a0 []*gitlab.Merge
 	return _c
 }
 
-func (_c *GitLabClient_ListProjectMergeRequests_Call) RunAndReturn(run func(int, *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)) *GitLabClient_ListProjectMergeRequests_Call {
+func (_c *GitLabClient_ListProjectMergeRequests_Call) RunAndReturn(run func(context.Context, int, *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error)) *GitLabClient_ListProjectMergeRequests_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListSubGroups provides a mock function with given fields: groupID, opt, options
-func (_m *GitLabClient) ListSubGroups(groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error) {
+// ListSubGroups provides a mock function with given fields: ctx, groupID, opt, options
+func (_m *GitLabClient) ListSubGroups(ctx context.Context, groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error) {
 	_va := make([]interface{}, len(options))
 	for _i := range options {
 		_va[_i] = options[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, groupID, opt)
+	_ca = append(_ca, ctx, groupID, opt)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 []*gitlab.Group
 	var r1 *gitlab.Response
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)); ok {
-		return rf(groupID, opt, options...)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)); ok {
+		return rf(ctx, groupID, opt, options...)
 	}
-	if rf, ok := ret.Get(0).(func(int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) []*gitlab.Group); ok {
-		r0 = rf(groupID, opt, options...)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) []*gitlab.Group); ok {
+		r0 = rf(ctx, groupID, opt, options...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*gitlab.Group)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) *gitlab.Response); ok {
-		r1 = rf(groupID, opt, options...)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) *gitlab.Response); ok {
+		r1 = rf(ctx, groupID, opt, options...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*gitlab.Response)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) error); ok {
-		r2 = rf(groupID, opt, options...)
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) error); ok {
+		r2 = rf(ctx, groupID, opt, options...)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -260,23 +801,24 @@ type GitLabClient_ListSubGroups_Call struct {
 }
 
 // ListSubGroups is a helper method to define mock.On call
+//   - ctx context.Context
 //   - groupID int
 //   - opt *gitlab.ListSubGroupsOptions
 //   - options ...gitlab.RequestOptionFunc
-func (_e *GitLabClient_Expecter) ListSubGroups(groupID interface{}, opt interface{}, options ...interface{}) *GitLabClient_ListSubGroups_Call {
+func (_e *GitLabClient_Expecter) ListSubGroups(ctx interface{}, groupID interface{}, opt interface{}, options ...interface{}) *GitLabClient_ListSubGroups_Call {
 	return &GitLabClient_ListSubGroups_Call{Call: _e.mock.On("ListSubGroups",
-		append([]interface{}{groupID, opt}, options...)...)}
+		append([]interface{}{ctx, groupID, opt}, options...)...)}
 }
 
-func (_c *GitLabClient_ListSubGroups_Call) Run(run func(groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc)) *GitLabClient_ListSubGroups_Call {
+func (_c *GitLabClient_ListSubGroups_Call) Run(run func(ctx context.Context, groupID int, opt *gitlab.ListSubGroupsOptions, options ...gitlab.RequestOptionFunc)) *GitLabClient_ListSubGroups_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		variadicArgs := make([]gitlab.RequestOptionFunc, len(args)-2)
-		for i, a := range args[2:] {
+		variadicArgs := make([]gitlab.RequestOptionFunc, len(args)-3)
+		for i, a := range args[3:] {
 			if a != nil {
 				variadicArgs[i] = a.(gitlab.RequestOptionFunc)
 			}
 		}
-		run(args[0].(int), args[1].(*gitlab.ListSubGroupsOptions), variadicArgs...)
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.ListSubGroupsOptions), variadicArgs...)
 	})
 	return _c
 }
@@ -286,7 +828,138 @@ func (_c *GitLabClient_ListSubGroups_Call) Return(_a0 []*gitlab.Group, _a1 *gitl
 	return _c
 }
 
-func (_c *GitLabClient_ListSubGroups_Call) RunAndReturn(run func(int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)) *GitLabClient_ListSubGroups_Call {
+func (_c *GitLabClient_ListSubGroups_Call) RunAndReturn(run func(context.Context, int, *gitlab.ListSubGroupsOptions, ...gitlab.RequestOptionFunc) ([]*gitlab.Group, *gitlab.Response, error)) *GitLabClient_ListSubGroups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWikis provides a mock function with given fields: ctx, projectID, options
+func (_m *GitLabClient) ListWikis(ctx context.Context, projectID int, options *gitlab.ListWikisOptions) ([]*gitlab.Wiki, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, options)
+
+	var r0 []*gitlab.Wiki
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListWikisOptions) ([]*gitlab.Wiki, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *gitlab.ListWikisOptions) []*gitlab.Wiki); ok {
+		r0 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gitlab.Wiki)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *gitlab.ListWikisOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, *gitlab.ListWikisOptions) error); ok {
+		r2 = rf(ctx, projectID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_ListWikis_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWikis'
+type GitLabClient_ListWikis_Call struct {
+	*mock.Call
+}
+
+// ListWikis is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - options *gitlab.ListWikisOptions
+func (_e *GitLabClient_Expecter) ListWikis(ctx interface{}, projectID interface{}, options interface{}) *GitLabClient_ListWikis_Call {
+	return &GitLabClient_ListWikis_Call{Call: _e.mock.On("ListWikis", ctx, projectID, options)}
+}
+
+func (_c *GitLabClient_ListWikis_Call) Run(run func(ctx context.Context, projectID int, options *gitlab.ListWikisOptions)) *GitLabClient_ListWikis_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*gitlab.ListWikisOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_ListWikis_Call) Return(_a0 []*gitlab.Wiki, _a1 *gitlab.Response, _a2 error) *GitLabClient_ListWikis_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_ListWikis_Call) RunAndReturn(run func(context.Context, int, *gitlab.ListWikisOptions) ([]*gitlab.Wiki, *gitlab.Response, error)) *GitLabClient_ListWikis_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateIssue provides a mock function with given fields: ctx, projectID, issueIID, options
+func (_m *GitLabClient) UpdateIssue(ctx context.Context, projectID int, issueIID int, options *gitlab.UpdateIssueOptions) (*gitlab.Issue, *gitlab.Response, error) {
+	ret := _m.Called(ctx, projectID, issueIID, options)
+
+	var r0 *gitlab.Issue
+	var r1 *gitlab.Response
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *gitlab.UpdateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)); ok {
+		return rf(ctx, projectID, issueIID, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *gitlab.UpdateIssueOptions) *gitlab.Issue); ok {
+		r0 = rf(ctx, projectID, issueIID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gitlab.Issue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *gitlab.UpdateIssueOptions) *gitlab.Response); ok {
+		r1 = rf(ctx, projectID, issueIID, options)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*gitlab.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, *gitlab.UpdateIssueOptions) error); ok {
+		r2 = rf(ctx, projectID, issueIID, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GitLabClient_UpdateIssue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIssue'
+type GitLabClient_UpdateIssue_Call struct {
+	*mock.Call
+}
+
+// UpdateIssue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projectID int
+//   - issueIID int
+//   - options *gitlab.UpdateIssueOptions
+func (_e *GitLabClient_Expecter) UpdateIssue(ctx interface{}, projectID interface{}, issueIID interface{}, options interface{}) *GitLabClient_UpdateIssue_Call {
+	return &GitLabClient_UpdateIssue_Call{Call: _e.mock.On("UpdateIssue", ctx, projectID, issueIID, options)}
+}
+
+func (_c *GitLabClient_UpdateIssue_Call) Run(run func(ctx context.Context, projectID int, issueIID int, options *gitlab.UpdateIssueOptions)) *GitLabClient_UpdateIssue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*gitlab.UpdateIssueOptions))
+	})
+	return _c
+}
+
+func (_c *GitLabClient_UpdateIssue_Call) Return(_a0 *gitlab.Issue, _a1 *gitlab.Response, _a2 error) *GitLabClient_UpdateIssue_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *GitLabClient_UpdateIssue_Call) RunAndReturn(run func(context.Context, int, int, *gitlab.UpdateIssueOptions) (*gitlab.Issue, *gitlab.Response, error)) *GitLabClient_UpdateIssue_Call {
 	_c.Call.Return(run)
 	return _c
 }
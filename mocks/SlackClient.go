@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	slack "github.com/slack-go/slack"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -20,13 +21,13 @@ func (_m *SlackClient) EXPECT() *SlackClient_Expecter {
 	return &SlackClient_Expecter{mock: &_m.Mock}
 }
 
-// PostWebhook provides a mock function with given fields: payload
-func (_m *SlackClient) PostWebhook(payload *slack.WebhookMessage) error {
-	ret := _m.Called(payload)
+// PostWebhook provides a mock function with given fields: ctx, payload
+func (_m *SlackClient) PostWebhook(ctx context.Context, payload *slack.WebhookMessage) error {
+	ret := _m.Called(ctx, payload)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*slack.WebhookMessage) error); ok {
-		r0 = rf(payload)
+	if rf, ok := ret.Get(0).(func(context.Context, *slack.WebhookMessage) error); ok {
+		r0 = rf(ctx, payload)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,14 +41,15 @@ type SlackClient_PostWebhook_Call struct {
 }
 
 // PostWebhook is a helper method to define mock.On call
+//   - ctx context.Context
 //   - payload *slack.WebhookMessage
-func (_e *SlackClient_Expecter) PostWebhook(payload interface{}) *SlackClient_PostWebhook_Call {
-	return &SlackClient_PostWebhook_Call{Call: _e.mock.On("PostWebhook", payload)}
+func (_e *SlackClient_Expecter) PostWebhook(ctx interface{}, payload interface{}) *SlackClient_PostWebhook_Call {
+	return &SlackClient_PostWebhook_Call{Call: _e.mock.On("PostWebhook", ctx, payload)}
 }
 
-func (_c *SlackClient_PostWebhook_Call) Run(run func(payload *slack.WebhookMessage)) *SlackClient_PostWebhook_Call {
+func (_c *SlackClient_PostWebhook_Call) Run(run func(ctx context.Context, payload *slack.WebhookMessage)) *SlackClient_PostWebhook_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*slack.WebhookMessage))
+		run(args[0].(context.Context), args[1].(*slack.WebhookMessage))
 	})
 	return _c
 }
@@ -57,7 +59,7 @@ func (_c *SlackClient_PostWebhook_Call) Return(_a0 error) *SlackClient_PostWebho
 	return _c
 }
 
-func (_c *SlackClient_PostWebhook_Call) RunAndReturn(run func(*slack.WebhookMessage) error) *SlackClient_PostWebhook_Call {
+func (_c *SlackClient_PostWebhook_Call) RunAndReturn(run func(context.Context, *slack.WebhookMessage) error) *SlackClient_PostWebhook_Call {
 	_c.Call.Return(run)
 	return _c
 }
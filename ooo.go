@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultAwayEmojis are GitLab status emojis commonly set while out of office.
+var defaultAwayEmojis = []string{"palm_tree", "beach_umbrella", "desert_island", "airplane", "face_with_thermometer", "thermometer"}
+
+// absence is an entry of the out-of-office file. From and Until are
+// inclusive YYYY-MM-DD dates, the absence is open-ended if they are empty.
+type absence struct {
+	Username string `yaml:"username"`
+	From     string `yaml:"from"`
+	Until    string `yaml:"until"`
+}
+
+func (a absence) covers(date string) bool {
+	return (a.From == "" || a.From <= date) && (a.Until == "" || date <= a.Until)
+}
+
+// readAbsences reads the out-of-office file, a YAML list of absences.
+func readAbsences(file string) ([]absence, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading out-of-office file: %w", err)
+	}
+
+	var absences []absence
+	if err := yaml.Unmarshal(data, &absences); err != nil {
+		return nil, fmt.Errorf("error parsing out-of-office file: %w", err)
+	}
+
+	for _, a := range absences {
+		if a.Username == "" {
+			return nil, fmt.Errorf("username is required in out-of-office file")
+		}
+		for _, date := range []string{a.From, a.Until} {
+			if _, err := time.Parse(dateLayout, date); date != "" && err != nil {
+				return nil, fmt.Errorf("invalid date %q of %s in out-of-office file, must be in YYYY-MM-DD format", date, a.Username)
+			}
+		}
+	}
+
+	return absences, nil
+}
+
+// awayChecker tells whether users are away, asking GitLab once per user.
+type awayChecker struct {
+	config   ConfigOutOfOffice
+	client   GitLabClient
+	absences []absence
+	date     string
+	away     map[int]bool
+}
+
+func (c *awayChecker) isAway(ctx context.Context, user *gitlab.BasicUser) (bool, error) {
+	if away, ok := c.away[user.ID]; ok {
+		return away, nil
+	}
+
+	away := slices.ContainsFunc(c.absences, func(a absence) bool {
+		return a.Username == user.Username && a.covers(c.date)
+	})
+
+	if !away && c.config.GitLabStatus {
+		status, _, err := c.client.GetUserStatus(ctx, user.ID)
+		if err != nil {
+			return false, fmt.Errorf("error fetching status of user %s: %w", user.Username, err)
+		}
+
+		emojis := c.config.Emojis
+		if len(emojis) == 0 {
+			emojis = defaultAwayEmojis
+		}
+		away = status.Availability == gitlab.Busy || slices.Contains(emojis, status.Emoji)
+	}
+
+	c.away[user.ID] = away
+	return away, nil
+}
+
+// markAwayUsers annotates merge requests whose author or reviewers are out of
+// office, and suggests replacements for away reviewers if configured.
+func markAwayUsers(ctx context.Context, config *Config, client GitLabClient, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	cfg := config.OutOfOffice
+	if !cfg.GitLabStatus && cfg.File == "" {
+		return nil
+	}
+
+	if config.location != nil {
+		now = now.In(config.location)
+	}
+
+	checker := &awayChecker{
+		config: cfg,
+		client: client,
+		date:   now.Format(dateLayout),
+		away:   make(map[int]bool),
+	}
+
+	if cfg.File != "" {
+		var err error
+		checker.absences, err = readAbsences(cfg.File)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, mr := range mrs {
+		if author := mr.MergeRequest.Author; author != nil {
+			away, err := checker.isAway(ctx, author)
+			if err != nil {
+				return err
+			}
+			mr.AuthorAway = away
+		}
+
+		for _, reviewer := range mr.MergeRequest.Reviewers {
+			away, err := checker.isAway(ctx, reviewer)
+			if err != nil {
+				return err
+			}
+			if away {
+				mr.AwayReviewers = append(mr.AwayReviewers, reviewer.Name)
+			}
+		}
+	}
+
+	if cfg.SuggestReviewer {
+		suggestReviewers(mrs, checker.away)
+	}
+	return nil
+}
+
+// suggestReviewers picks a replacement for away reviewers among reviewers of
+// other merge requests of the same group who are not away, preferring the
+// ones with the fewest reviews.
+func suggestReviewers(mrs []*MergeRequestWithApprovals, away map[int]bool) {
+	type candidate struct {
+		user    *gitlab.BasicUser
+		reviews int
+	}
+
+	candidatesByGroup := make(map[string]map[int]*candidate)
+	for _, mr := range mrs {
+		group := path.Dir(mr.ProjectName)
+		if candidatesByGroup[group] == nil {
+			candidatesByGroup[group] = make(map[int]*candidate)
+		}
+		for _, reviewer := range mr.MergeRequest.Reviewers {
+			if away[reviewer.ID] {
+				continue
+			}
+			c, ok := candidatesByGroup[group][reviewer.ID]
+			if !ok {
+				c = &candidate{user: reviewer}
+				candidatesByGroup[group][reviewer.ID] = c
+			}
+			c.reviews++
+		}
+	}
+
+	for _, mr := range mrs {
+		if len(mr.AwayReviewers) == 0 {
+			continue
+		}
+
+		var candidates []*candidate
+		for _, c := range candidatesByGroup[path.Dir(mr.ProjectName)] {
+			if isReviewer(mr.MergeRequest, c.user.ID) || (mr.MergeRequest.Author != nil && mr.MergeRequest.Author.ID == c.user.ID) {
+				continue
+			}
+			candidates = append(candidates, c)
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].reviews != candidates[j].reviews {
+				return candidates[i].reviews < candidates[j].reviews
+			}
+			return candidates[i].user.Name < candidates[j].user.Name
+		})
+		mr.SuggestedReviewer = candidates[0].user.Name
+	}
+}
+
+func isReviewer(mr *gitlab.MergeRequest, userID int) bool {
+	return slices.ContainsFunc(mr.Reviewers, func(u *gitlab.BasicUser) bool { return u.ID == userID })
+}
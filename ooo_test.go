@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flexoid/mergentle-reminder/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestReadAbsences(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "ooo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`- username: janedoe
+  from: 2024-12-20
+  until: 2025-01-06
+- username: johndoe
+`), 0o644))
+
+	absences, err := readAbsences(path)
+	require.NoError(t, err)
+	require.Len(t, absences, 2)
+
+	assert.False(t, absences[0].covers("2024-12-19"))
+	assert.True(t, absences[0].covers("2024-12-20"))
+	assert.True(t, absences[0].covers("2025-01-06"))
+	assert.False(t, absences[0].covers("2025-01-07"))
+	assert.True(t, absences[1].covers("2030-01-01"))
+
+	require.NoError(t, os.WriteFile(path, []byte("- username: janedoe\n  until: 06.01.2025\n"), 0o644))
+	_, err = readAbsences(path)
+	assert.EqualError(t, err, `invalid date "06.01.2025" of janedoe in out-of-office file, must be in YYYY-MM-DD format`)
+
+	require.NoError(t, os.WriteFile(path, []byte("- from: 2025-01-06\n"), 0o644))
+	_, err = readAbsences(path)
+	assert.EqualError(t, err, "username is required in out-of-office file")
+}
+
+func TestMarkAwayUsers(t *testing.T) {
+	now := time.Date(2024, 12, 23, 9, 0, 0, 0, time.UTC)
+
+	jane := &gitlab.BasicUser{ID: 1, Username: "janedoe", Name: "Jane Doe"}
+	john := &gitlab.BasicUser{ID: 2, Username: "johndoe", Name: "John Doe"}
+	alice := &gitlab.BasicUser{ID: 3, Username: "alice", Name: "Alice"}
+	bob := &gitlab.BasicUser{ID: 4, Username: "bob", Name: "Bob"}
+	carol := &gitlab.BasicUser{ID: 5, Username: "carol", Name: "Carol"}
+
+	newMR := func(project string, author *gitlab.BasicUser, reviewers ...*gitlab.BasicUser) *MergeRequestWithApprovals {
+		return &MergeRequestWithApprovals{
+			MergeRequest: &gitlab.MergeRequest{Author: author, Reviewers: reviewers},
+			ProjectName:  project,
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "ooo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- username: janedoe\n  from: 2024-12-20\n"), 0o644))
+
+	mrs := []*MergeRequestWithApprovals{
+		newMR("group/alpha", alice, jane),
+		newMR("group/beta", jane, john, bob),
+		newMR("group/alpha", bob, carol, alice),
+		newMR("group/alpha", carol, alice),
+		newMR("other/gamma", alice, john),
+	}
+
+	client := mocks.NewGitLabClient(t)
+	client.EXPECT().GetUserStatus(mock.Anything, 2).Return(&gitlab.UserStatus{Emoji: "palm_tree"}, nil, nil).Once()
+	client.EXPECT().GetUserStatus(mock.Anything, 3).Return(&gitlab.UserStatus{Availability: gitlab.NotSet}, nil, nil).Once()
+	client.EXPECT().GetUserStatus(mock.Anything, 4).Return(&gitlab.UserStatus{Emoji: "coffee"}, nil, nil).Once()
+	client.EXPECT().GetUserStatus(mock.Anything, 5).Return(&gitlab.UserStatus{Availability: gitlab.Busy}, nil, nil).Once()
+
+	config := &Config{OutOfOffice: ConfigOutOfOffice{GitLabStatus: true, File: path, SuggestReviewer: true}}
+	require.NoError(t, markAwayUsers(context.Background(), config, client, mrs, now))
+
+	assert.False(t, mrs[0].AuthorAway)
+	assert.Equal(t, []string{"Jane Doe"}, mrs[0].AwayReviewers)
+	// Bob reviews in the same group and is not away, the author can't review.
+	assert.Equal(t, "Bob", mrs[0].SuggestedReviewer)
+
+	assert.True(t, mrs[1].AuthorAway)
+	assert.Equal(t, []string{"John Doe"}, mrs[1].AwayReviewers)
+	assert.Equal(t, "Alice", mrs[1].SuggestedReviewer)
+
+	// The only other candidates are the author and the current reviewer.
+	assert.Equal(t, []string{"Carol"}, mrs[2].AwayReviewers)
+	assert.Empty(t, mrs[2].SuggestedReviewer)
+
+	assert.True(t, mrs[3].AuthorAway)
+	assert.Empty(t, mrs[3].AwayReviewers)
+
+	// Nobody else reviews in the other group.
+	assert.Equal(t, []string{"John Doe"}, mrs[4].AwayReviewers)
+	assert.Empty(t, mrs[4].SuggestedReviewer)
+
+	t.Run("suggests least busy reviewer of the group", func(t *testing.T) {
+		mrs := []*MergeRequestWithApprovals{
+			newMR("group/alpha", alice, jane),
+			newMR("group/beta", alice, bob),
+			newMR("group/beta", alice, bob),
+			newMR("group/gamma", bob, carol),
+		}
+		mrs[0].AwayReviewers = []string{"Jane Doe"}
+
+		suggestReviewers(mrs, map[int]bool{jane.ID: true})
+		// Reviewers of group/beta and group/gamma are all in the "group" group.
+		assert.Equal(t, "Carol", mrs[0].SuggestedReviewer)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mrs := []*MergeRequestWithApprovals{newMR("group/alpha", alice, jane)}
+		require.NoError(t, markAwayUsers(context.Background(), &Config{}, mocks.NewGitLabClient(t), mrs, now))
+		assert.Empty(t, mrs[0].AwayReviewers)
+	})
+
+	t.Run("rendering", func(t *testing.T) {
+		mr := newMR("group/alpha", alice, jane)
+		mr.MergeRequest.CreatedAt = &now
+		mr.AuthorAway = true
+		mr.AwayReviewers = []string{"Jane Doe"}
+		mr.SuggestedReviewer = "Bob"
+
+		summary, err := formatMergeRequestsSummary([]*MergeRequestWithApprovals{mr}, &Config{}, now)
+		require.NoError(t, err)
+		assert.Contains(t, summary, ":palm_tree: Author away\n")
+		assert.Contains(t, summary, ":palm_tree: Reviewer away (Jane Doe) — consider reassigning. Suggested reviewer: Bob\n")
+
+		catalog, err := catalogFor("")
+		require.NoError(t, err)
+		details := mergeRequestDetails(buildSummary([]*MergeRequestWithApprovals{mr}, &Config{}, catalog, now), newMergeRequestView(mr, catalog, nil))
+		assert.Equal(t, []string{"Author away", "Reviewer away (Jane Doe) — consider reassigning", "Suggested reviewer: Bob"}, details[len(details)-3:])
+	})
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDelay groups bursts of file system events, e.g. editors writing
+// a file in several steps or Kubernetes swapping a ConfigMap symlink.
+const configReloadDelay = 500 * time.Millisecond
+
+// watchConfig calls reload whenever the content of the file at path, or of
+// one of the files returned by files, changes, until ctx is done. Directories
+// are watched rather than files, so that replacing a file or a symlink
+// pointing to it is also noticed. Files are listed again after each reload,
+// as the configuration may reference other ones.
+func watchConfig(ctx context.Context, path string, files func() []string, reload func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating file watcher: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("error watching %s: %w", path, err)
+	}
+
+	var paths []string
+	var contents map[string][]byte
+	watchFiles := func() {
+		paths = append([]string{path}, files()...)
+		for _, file := range paths[1:] {
+			if err := watcher.Add(filepath.Dir(file)); err != nil {
+				log.Printf("Changes of %s will not be applied: %v", file, err)
+			}
+		}
+		contents = readWatchedFiles(paths)
+	}
+	watchFiles()
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(configReloadDelay)
+		timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				timer.Reset(configReloadDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("Error watching configuration file: %v", err)
+			case <-timer.C:
+				changed := changedFiles(contents, readWatchedFiles(paths))
+				if len(changed) == 0 {
+					continue
+				}
+
+				log.Printf("Configuration file %s changed, reloading.", strings.Join(changed, ", "))
+				reload()
+				watchFiles()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// readWatchedFiles returns contents of files by path. Files that can't be read
+// are left out, so that nothing is reloaded while they are being replaced.
+func readWatchedFiles(paths []string) map[string][]byte {
+	contents := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		if data, err := os.ReadFile(path); err == nil {
+			contents[path] = data
+		}
+	}
+	return contents
+}
+
+// changedFiles returns sorted paths of files read in after whose contents
+// differ from before.
+func changedFiles(before, after map[string][]byte) []string {
+	var changed []string
+	for path, content := range after {
+		if !bytes.Equal(before[path], content) {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// referencedFiles returns paths of files read by loadConfig, keyed by the
+// configuration key referencing them, e.g. "teams[0].template_file". Secret
+// files are not included, their contents are compared as values of the keys
+// they set. The out-of-office file is read at each run and needs no reload.
+func (c *Config) referencedFiles() map[string]string {
+	files := make(map[string]string)
+	add := func(key, file string) {
+		if file != "" {
+			files[key] = file
+		}
+	}
+
+	var addConfig func(prefix string, config *Config)
+	addConfig = func(prefix string, config *Config) {
+		add(prefix+"template_file", config.TemplateFile)
+		for i, schedule := range config.Schedules {
+			add(fmt.Sprintf("%sschedules[%d].template_file", prefix, i), schedule.TemplateFile)
+		}
+		add(prefix+"webhook.template_file", config.Webhook.TemplateFile)
+		add(prefix+"calendar.ics_file", config.Calendar.ICSFile)
+		add(prefix+"calendar.holidays_file", config.Calendar.HolidaysFile)
+
+		for i := range config.Teams {
+			addConfig(fmt.Sprintf("%steams[%d].", prefix, i), &config.Teams[i].Config)
+		}
+	}
+	addConfig("", c)
+
+	return files
+}
+
+// readReferencedFiles returns contents of files referenced by the
+// configuration, keyed like referencedFiles. Files that can't be read are left
+// out, loading them has already been reported.
+func (c *Config) readReferencedFiles() map[string]string {
+	contents := make(map[string]string)
+	for key, file := range c.referencedFiles() {
+		if data, err := os.ReadFile(file); err == nil {
+			contents[key] = string(data)
+		}
+	}
+	return contents
+}
+
+// configChanges returns paths of configuration keys whose values differ,
+// e.g. "slack.webhook_url" or "teams[1].cron_schedule". Keys referencing files
+// whose contents differ are included too. Values are left out as they may
+// contain secrets.
+func configChanges(before, after *Config) []string {
+	if before == nil {
+		before = &Config{}
+	}
+	changes := valueChanges("", reflect.ValueOf(*before), reflect.ValueOf(*after))
+
+	for _, key := range slices.Sorted(maps.Keys(after.fileContents)) {
+		if after.fileContents[key] != before.fileContents[key] && !slices.Contains(changes, key) {
+			changes = append(changes, key)
+		}
+	}
+	return changes
+}
+
+func valueChanges(path string, before, after reflect.Value) []string {
+	switch before.Kind() {
+	case reflect.Struct:
+		var changes []string
+		for i := 0; i < before.NumField(); i++ {
+			field := before.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+
+			fieldPath := path
+			if opts != "inline" {
+				fieldPath = joinConfigPath(path, name)
+			}
+			changes = append(changes, valueChanges(fieldPath, before.Field(i), after.Field(i))...)
+		}
+		return changes
+	case reflect.Slice:
+		if before.Len() == after.Len() && before.Type().Elem().Kind() == reflect.Struct {
+			var changes []string
+			for i := 0; i < before.Len(); i++ {
+				changes = append(changes, valueChanges(fmt.Sprintf("%s[%d]", path, i), before.Index(i), after.Index(i))...)
+			}
+			return changes
+		}
+	}
+
+	if reflect.DeepEqual(before.Interface(), after.Interface()) {
+		return nil
+	}
+	return []string{path}
+}
+
+func joinConfigPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
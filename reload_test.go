@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("cron_schedule: \"0 0 9 * * *\"\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	templatePath := filepath.Join(t.TempDir(), "template.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("first"), 0o644))
+
+	reloaded := make(chan struct{}, 10)
+	files := func() []string { return []string{templatePath} }
+	require.NoError(t, watchConfig(ctx, path, files, func() { reloaded <- struct{}{} }))
+
+	// Rewriting the same content is not a change.
+	require.NoError(t, os.WriteFile(path, []byte("cron_schedule: \"0 0 9 * * *\"\n"), 0o644))
+	select {
+	case <-reloaded:
+		t.Fatal("unexpected reload")
+	case <-time.After(2 * configReloadDelay):
+	}
+
+	// Replace the file the way editors and ConfigMaps do.
+	tmp := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("cron_schedule: \"0 0 10 * * *\"\n"), 0o644))
+	require.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	// Files referenced by the configuration are watched too.
+	require.NoError(t, os.WriteFile(templatePath, []byte("second"), 0o644))
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded after the template changed")
+	}
+}
+
+func TestConfigChanges(t *testing.T) {
+	before := &Config{
+		CronSchedule: "0 0 9 * * *",
+		Slack:        ConfigSlack{WebhookURL: "https://hooks.slack.com/services/xxx"},
+		Projects:     []ConfigProject{{ID: 1}},
+		Teams: []ConfigTeam{
+			{Name: "backend", Config: Config{SortBy: SortByAge}},
+		},
+	}
+
+	assert.Empty(t, configChanges(before, before))
+
+	after := &Config{
+		CronSchedule: "0 0 10 * * *",
+		Slack:        ConfigSlack{WebhookURL: "https://hooks.slack.com/services/yyy"},
+		Projects:     []ConfigProject{{ID: 1}, {ID: 2}},
+		Teams: []ConfigTeam{
+			{Name: "backend", Config: Config{SortBy: SortByProject}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"slack.webhook_url",
+		"projects",
+		"cron_schedule",
+		"teams[0].sort_by",
+	}, configChanges(before, after))
+
+	// Contents of referenced files are compared even if their paths are the same.
+	before = &Config{TemplateFile: "summary.tmpl", fileContents: map[string]string{"template_file": "first"}}
+	after = &Config{TemplateFile: "summary.tmpl", fileContents: map[string]string{"template_file": "second"}}
+	assert.Equal(t, []string{"template_file"}, configChanges(before, after))
+	assert.Empty(t, configChanges(after, after))
+}
+
+func TestReferencedFiles(t *testing.T) {
+	config := &Config{
+		TemplateFile: "summary.tmpl",
+		Schedules:    []ConfigSchedule{{Cron: "0 0 9 * * 1"}, {Cron: "0 0 9 * * 5", TemplateFile: "weekly.tmpl"}},
+		Teams: []ConfigTeam{
+			{Name: "backend", Config: Config{Calendar: ConfigCalendar{ICSFile: "holidays.ics", HolidaysFile: "holidays.yaml"}}},
+			{Name: "mobile", Config: Config{Webhook: ConfigWebhook{TemplateFile: "webhook.tmpl"}}},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"template_file":                   "summary.tmpl",
+		"schedules[1].template_file":      "weekly.tmpl",
+		"teams[0].calendar.ics_file":      "holidays.ics",
+		"teams[0].calendar.holidays_file": "holidays.yaml",
+		"teams[1].webhook.template_file":  "webhook.tmpl",
+	}, config.referencedFiles())
+}
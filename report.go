@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	ReportFormatMarkdown = "markdown"
+	ReportFormatHTML     = "html"
+)
+
+const markdownReportTemplate = `# {{ t "report_title" }}
+
+_{{ t "generated_at" }}: {{ formatDate .Now (t "date_layout") }}_
+{{ range .ByProject }}
+## {{ md .Name }}
+
+| {{ t "merge_request" }} | {{ t "author" }} | {{ t "state" }} | {{ t "created_at" }} | {{ t "approved_by" }} | {{ t "reviewers" }} |
+| --- | --- | --- | --- | --- | --- |
+{{ range .MergeRequests -}}
+| [!{{ .IID }} {{ md .Title }}]({{ .URL }}) | {{ md .Author }} | {{ .State }} | {{ formatDate .CreatedAt (t "date_layout") }} | {{ md (join .ApprovedBy ", ") }} | {{ md (join .Reviewers ", ") }} |
+{{ end -}}
+{{ else }}
+{{ t "no_merge_requests" }}
+{{ end -}}
+{{ if .Omitted }}
+{{ if .MoreURL }}[{{ t "and_more" .Omitted }}]({{ .MoreURL }}){{ else }}{{ t "and_more" .Omitted }}{{ end }}
+{{ end -}}
+`
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="{{ .Language }}">
+<head>
+<meta charset="utf-8">
+<title>{{ t "report_title" }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 6px 12px; text-align: left; }
+th { background: #f6f8fa; }
+.generated { color: #57606a; }
+</style>
+</head>
+<body>
+<h1>{{ t "report_title" }}</h1>
+<p class="generated">{{ t "generated_at" }}: {{ formatDate .Now (t "date_layout") }}</p>
+{{ range .ByProject -}}
+<h2>{{ .Name }}</h2>
+<table>
+<tr><th>{{ t "merge_request" }}</th><th>{{ t "author" }}</th><th>{{ t "state" }}</th><th>{{ t "created_at" }}</th><th>{{ t "approved_by" }}</th><th>{{ t "reviewers" }}</th></tr>
+{{ range .MergeRequests -}}
+<tr><td><a href="{{ .URL }}">!{{ .IID }} {{ .Title }}</a></td><td>{{ .Author }}</td><td>{{ .State }}</td><td>{{ formatDate .CreatedAt (t "date_layout") }}</td><td>{{ join .ApprovedBy ", " }}</td><td>{{ join .Reviewers ", " }}</td></tr>
+{{ end -}}
+</table>
+{{ else -}}
+<p>{{ t "no_merge_requests" }}</p>
+{{ end -}}
+{{ if .Omitted -}}
+<p>{{ if .MoreURL }}<a href="{{ .MoreURL }}">{{ t "and_more" .Omitted }}</a>{{ else }}{{ t "and_more" .Omitted }}{{ end }}</p>
+{{ end -}}
+</body>
+</html>
+`
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;", "\n", " ",
+)
+
+// escapeMarkdown escapes text for use inside a Markdown table cell.
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+func renderMarkdownReport(summary *Summary) (string, error) {
+	funcs := templateFuncs(summary.Now, summary.businessDays, summary.catalog)
+	funcs["md"] = escapeMarkdown
+
+	tmpl, err := template.New("markdown").Funcs(funcs).Parse(markdownReportTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, summary); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func renderHTMLReport(summary *Summary) (string, error) {
+	tmpl, err := htmltemplate.New("html").
+		Funcs(htmltemplate.FuncMap(templateFuncs(summary.Now, summary.businessDays, summary.catalog))).
+		Parse(htmlReportTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, summary); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func renderReport(format string, summary *Summary) (string, error) {
+	switch format {
+	case ReportFormatMarkdown:
+		return renderMarkdownReport(summary)
+	case ReportFormatHTML:
+		return renderHTMLReport(summary)
+	default:
+		return "", fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// writeReports renders every configured report to its path.
+func writeReports(config *Config, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	for _, report := range config.Reports {
+		catalog, err := catalogFor(report.Language)
+		if err != nil {
+			return err
+		}
+
+		content, err := renderReport(report.Format, buildSummary(mrs, config, catalog, now))
+		if err != nil {
+			return fmt.Errorf("error rendering %s report: %w", report.Format, err)
+		}
+
+		if err := os.WriteFile(report.Path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("error writing %s report: %w", report.Format, err)
+		}
+	}
+
+	return nil
+}
+
+func validateReport(report ConfigReport) error {
+	if report.Format != ReportFormatMarkdown && report.Format != ReportFormatHTML {
+		return fmt.Errorf("invalid report format %q, must be one of: %s, %s", report.Format, ReportFormatMarkdown, ReportFormatHTML)
+	}
+	if report.Path == "" {
+		return fmt.Errorf("path of the %s report is required", report.Format)
+	}
+	_, err := catalogFor(report.Language)
+	return err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReport(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	summary := buildSummary(testMergeRequestsForExport(), &Config{}, catalogs["en"], now)
+
+	t.Run("markdown", func(t *testing.T) {
+		report, err := renderReport(ReportFormatMarkdown, summary)
+		require.NoError(t, err)
+		assertGolden(t, "report.md", []byte(report))
+	})
+
+	t.Run("html", func(t *testing.T) {
+		report, err := renderReport(ReportFormatHTML, summary)
+		require.NoError(t, err)
+		assertGolden(t, "report.html", []byte(report))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := renderReport("pdf", summary)
+		assert.ErrorContains(t, err, `unsupported report format "pdf"`)
+	})
+}
+
+func TestRenderReport_Empty(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	summary := buildSummary(nil, &Config{}, catalogs["en"], now)
+
+	report, err := renderMarkdownReport(summary)
+	require.NoError(t, err)
+	assert.Contains(t, report, "No opened merge requests found.")
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	assert.Equal(t, `Fix \| and \*bold\* \[link\] &lt;b&gt;`, escapeMarkdown("Fix | and *bold* [link] <b>"))
+}
+
+func TestWriteReports(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	config := &Config{Reports: []ConfigReport{
+		{Format: ReportFormatHTML, Path: filepath.Join(dir, "report.html"), Language: "pl"},
+	}}
+
+	require.NoError(t, writeReports(config, testMergeRequestsForExport(), now))
+
+	data, err := os.ReadFile(config.Reports[0].Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<html lang="pl">`)
+	assert.Contains(t, string(data), "<h1>Otwarte merge requesty</h1>")
+}
+
+func TestValidateReport(t *testing.T) {
+	assert.NoError(t, validateReport(ConfigReport{Format: ReportFormatMarkdown, Path: "report.md"}))
+	assert.ErrorContains(t, validateReport(ConfigReport{Format: "pdf", Path: "report.pdf"}), "invalid report format")
+	assert.ErrorContains(t, validateReport(ConfigReport{Format: ReportFormatHTML}), "path of the html report is required")
+	assert.ErrorContains(t, validateReport(ConfigReport{Format: ReportFormatHTML, Path: "r.html", Language: "xx"}), "unsupported language")
+}
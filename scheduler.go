@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reugn/go-quartz/job"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// scheduler runs teams according to their cron schedules and replaces the
+// scheduled jobs when the configuration is reloaded.
+type scheduler struct {
+	sched quartz.Scheduler
+	env   Env
+
+	// runs is the parent context of scheduled runs, cancelled when they don't
+	// finish within the shutdown timeout.
+	runs       context.Context
+	cancelRuns context.CancelFunc
+
+	mu     sync.Mutex
+	config *Config
+}
+
+func newScheduler(sched quartz.Scheduler, env Env) *scheduler {
+	runs, cancelRuns := context.WithCancel(context.Background())
+	return &scheduler{sched: sched, env: env, runs: runs, cancelRuns: cancelRuns}
+}
+
+// cronJob runs teams sharing a cron schedule and timezone.
+type cronJob struct {
+	schedule string
+	location *time.Location
+	teams    []*Config
+}
+
+// cronJobs returns jobs of all schedules of all teams, in order of first
+// appearance. Teams sharing a schedule run in a single job to share fetched data.
+func cronJobs(config *Config) []*cronJob {
+	var jobs []*cronJob
+	add := func(schedule string, location *time.Location, team *Config) {
+		if location == nil {
+			location = time.UTC
+		}
+		for _, existing := range jobs {
+			if existing.schedule == schedule && existing.location.String() == location.String() {
+				existing.teams = append(existing.teams, team)
+				return
+			}
+		}
+		jobs = append(jobs, &cronJob{schedule: schedule, location: location, teams: []*Config{team}})
+	}
+
+	for _, team := range config.teams() {
+		if team.CronSchedule != "" {
+			add(team.CronSchedule, team.cronLocation, team)
+		}
+		for i := range team.Schedules {
+			schedule := &team.Schedules[i]
+			add(schedule.Cron, schedule.location, team.forSchedule(schedule))
+		}
+	}
+	return jobs
+}
+
+// apply replaces scheduled jobs with the ones of config. Nothing is changed if
+// the GitLab client or any of the triggers can't be created.
+func (s *scheduler) apply(config *Config) error {
+	if !config.scheduled() {
+		return fmt.Errorf("cron_schedule or schedules are required in cron mode")
+	}
+
+	gitlabClient, err := newGitLabClient(config)
+	if err != nil {
+		return err
+	}
+
+	jobs := cronJobs(config)
+
+	triggers := make([]*quartz.CronTrigger, len(jobs))
+	for i, cronJob := range jobs {
+		triggers[i], err = quartz.NewCronTriggerWithLoc(cronJob.schedule, cronJob.location)
+		if err != nil {
+			return fmt.Errorf("error creating cron trigger: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sched.Clear(); err != nil {
+		return fmt.Errorf("error clearing scheduled jobs: %w", err)
+	}
+
+	for i, cronJob := range jobs {
+		teams := cronJob.teams
+
+		if len(config.Teams) == 0 {
+			log.Printf("Running in cron mode with schedule: %s (%s)", cronJob.schedule, cronJob.location)
+		} else {
+			log.Printf("Running in cron mode with schedule: %s (%s), teams: %s", cronJob.schedule, cronJob.location, strings.Join(teamNames(teams), ", "))
+		}
+
+		executeJob := job.NewFunctionJob(func(_ context.Context) (int, error) {
+			clock := &SystemClock{}
+
+			working := workingTeams(teams, clock.Now().In(cronJob.location))
+			if len(working) == 0 {
+				return 0, nil
+			}
+
+			ctx, cancel := config.withRunTimeout(s.runs)
+			defer cancel()
+
+			if err := executeTeams(ctx, working, gitlabClient, clock); err != nil {
+				log.Printf("Error during scheduled execution: %v", err)
+				return 1, err // Indicate failure
+			}
+			return 0, nil // Indicate success
+		})
+
+		err := s.sched.ScheduleJob(quartz.NewJobDetail(executeJob, quartz.NewJobKey(fmt.Sprintf("executeJob%d", i))), triggers[i])
+		if err != nil {
+			return fmt.Errorf("error scheduling job: %w", err)
+		}
+	}
+
+	s.config = config
+	return nil
+}
+
+// watchedFiles returns paths of files referenced by the active configuration.
+func (s *scheduler) watchedFiles() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config == nil {
+		return nil
+	}
+	return slices.Collect(maps.Values(s.config.referencedFiles()))
+}
+
+// reload loads the configuration again and applies it if it is valid and
+// changed. The previous configuration stays active otherwise.
+func (s *scheduler) reload() {
+	config, err := loadConfig(s.env)
+	if err != nil {
+		log.Printf("Error reloading configuration, keeping the previous one: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	changes := configChanges(s.config, config)
+	s.mu.Unlock()
+
+	if len(changes) == 0 {
+		log.Println("Configuration reloaded, no changes.")
+		return
+	}
+
+	if err := s.apply(config); err != nil {
+		log.Printf("Error applying reloaded configuration, keeping the previous one: %v", err)
+		return
+	}
+
+	log.Printf("Configuration reloaded, changed: %s", strings.Join(changes, ", "))
+}
+
+// shutdown stops scheduling runs and waits for the ones in progress to
+// finish, at most the shutdown timeout of the active configuration. Runs
+// still in progress after the timeout are cancelled.
+func (s *scheduler) shutdown() error {
+	s.mu.Lock()
+	timeout := s.config.shutdownTimeout
+	s.mu.Unlock()
+
+	s.sched.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.sched.Wait(context.Background())
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		s.cancelRuns()
+		return fmt.Errorf("scheduled run still in progress after shutdown timeout of %s", timeout)
+	}
+}
+
+// workingTeams returns teams for which the date of now is a working day,
+// logging the skipped ones.
+func workingTeams(teams []*Config, now time.Time) []*Config {
+	var working []*Config
+	for _, team := range teams {
+		name, ok := team.calendar.holiday(now)
+		if !ok {
+			working = append(working, team)
+			continue
+		}
+
+		reason := fmt.Sprintf("%s is a non-working day", now.Format(dateLayout))
+		if name != "" {
+			reason += fmt.Sprintf(" (%s)", name)
+		}
+
+		if team.team == "" {
+			log.Printf("Skipping scheduled run: %s.", reason)
+		} else {
+			log.Printf("Skipping scheduled run of team %s: %s.", team.team, reason)
+		}
+	}
+	return working
+}
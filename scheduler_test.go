@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/reugn/go-quartz/job"
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	writeConfig(`gitlab:
+  token: token
+cron_schedule: "0 0 9 * * 1-5"
+projects:
+  - id: 1
+slack:
+  webhook_url: https://hooks.slack.com/services/xxx
+`)
+
+	env := &MockEnv{values: map[string]string{"CONFIG_PATH": path}}
+	config, err := loadConfig(env)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sched := quartz.NewStdScheduler()
+	sched.Start(ctx)
+
+	s := newScheduler(sched, env)
+	require.NoError(t, s.apply(config))
+
+	jobKeys := func() []string {
+		keys, err := sched.GetJobKeys()
+		require.NoError(t, err)
+
+		var names []string
+		for _, key := range keys {
+			names = append(names, key.Name())
+		}
+		return names
+	}
+	assert.Equal(t, []string{"executeJob0"}, jobKeys())
+
+	t.Run("applies valid configuration", func(t *testing.T) {
+		writeConfig(`gitlab:
+  token: token
+teams:
+  - name: backend
+    cron_schedule: "0 0 9 * * 1-5"
+    projects:
+      - id: 1
+    slack:
+      webhook_url: https://hooks.slack.com/services/xxx
+  - name: mobile
+    cron_schedule: "0 0 10 * * 1-5"
+    projects:
+      - id: 2
+    slack:
+      webhook_url: https://hooks.slack.com/services/yyy
+`)
+		s.reload()
+
+		assert.ElementsMatch(t, []string{"executeJob0", "executeJob1"}, jobKeys())
+		assert.Len(t, s.config.Teams, 2)
+	})
+
+	t.Run("keeps previous configuration if invalid", func(t *testing.T) {
+		previous := s.config
+
+		writeConfig(`gitlab:
+  token: token
+cron_schedule: "every day"
+`)
+		s.reload()
+
+		assert.Same(t, previous, s.config)
+		assert.ElementsMatch(t, []string{"executeJob0", "executeJob1"}, jobKeys())
+	})
+
+	t.Run("keeps previous configuration without schedule", func(t *testing.T) {
+		previous := s.config
+
+		writeConfig(`gitlab:
+  token: token
+projects:
+  - id: 1
+slack:
+  webhook_url: https://hooks.slack.com/services/xxx
+`)
+		s.reload()
+
+		assert.Same(t, previous, s.config)
+	})
+
+	t.Run("applies changed template file", func(t *testing.T) {
+		templatePath := filepath.Join(filepath.Dir(path), "summary.tmpl")
+		require.NoError(t, os.WriteFile(templatePath, []byte("first"), 0o644))
+
+		writeConfig(`gitlab:
+  token: token
+cron_schedule: "0 0 9 * * 1-5"
+projects:
+  - id: 1
+slack:
+  webhook_url: https://hooks.slack.com/services/xxx
+template_file: ` + templatePath + `
+`)
+		s.reload()
+		previous := s.config
+
+		// Only the template changes, the configuration file stays the same.
+		require.NoError(t, os.WriteFile(templatePath, []byte("second"), 0o644))
+		s.reload()
+
+		require.NotSame(t, previous, s.config)
+		var sb strings.Builder
+		require.NoError(t, s.config.summaryTemplate.Execute(&sb, nil))
+		assert.Equal(t, "second", sb.String())
+	})
+}
+
+func TestCronJobs(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	ping := template.Must(parseSummaryTemplate("ping", "ping"))
+
+	config := &Config{Teams: []ConfigTeam{
+		{Name: "backend", Config: Config{
+			team:         "backend",
+			CronSchedule: "0 0 9 * * 1-5",
+			Slack:        ConfigSlack{WebhookURL: "https://hooks.slack.com/services/xxx"},
+			Discord:      ConfigDiscord{WebhookURL: "https://discord.com/api/webhooks/1/x"},
+			Schedules: []ConfigSchedule{
+				{Cron: "0 0 13 * * 1-5", Destinations: []string{"slack"}, summaryTemplate: ping},
+			},
+		}},
+		{Name: "mobile", Config: Config{
+			team:         "mobile",
+			CronSchedule: "0 0 9 * * 1-5",
+			cronLocation: warsaw,
+			Slack:        ConfigSlack{WebhookURL: "https://hooks.slack.com/services/yyy"},
+			Schedules: []ConfigSchedule{
+				{Cron: "0 0 13 * * 1-5"},
+			},
+		}},
+	}}
+
+	jobs := cronJobs(config)
+	require.Len(t, jobs, 3)
+
+	assert.Equal(t, "0 0 9 * * 1-5", jobs[0].schedule)
+	assert.Equal(t, time.UTC, jobs[0].location)
+	assert.Equal(t, []string{"backend"}, teamNames(jobs[0].teams))
+	assert.Len(t, newDestinations(jobs[0].teams[0], nil), 2)
+
+	assert.Equal(t, "0 0 13 * * 1-5", jobs[1].schedule)
+	assert.Equal(t, time.UTC, jobs[1].location)
+	assert.Equal(t, []string{"backend", "mobile"}, teamNames(jobs[1].teams))
+	assert.Same(t, ping, jobs[1].teams[0].summaryTemplate)
+	require.Len(t, newDestinations(jobs[1].teams[0], nil), 1)
+	assert.Equal(t, "Slack", newDestinations(jobs[1].teams[0], nil)[0].Name())
+	assert.Empty(t, jobs[1].teams[0].Discord.WebhookURL)
+	assert.Equal(t, "https://discord.com/api/webhooks/1/x", config.Teams[0].Discord.WebhookURL)
+
+	assert.Equal(t, "0 0 9 * * 1-5", jobs[2].schedule)
+	assert.Equal(t, warsaw, jobs[2].location)
+	assert.Equal(t, []string{"mobile"}, teamNames(jobs[2].teams))
+}
+
+func TestSchedulerShutdown(t *testing.T) {
+	run := func(t *testing.T, timeout time.Duration, release <-chan struct{}) (*scheduler, error) {
+		sched := quartz.NewStdScheduler()
+		sched.Start(context.Background())
+
+		started := make(chan struct{})
+		runJob := job.NewFunctionJob(func(_ context.Context) (int, error) {
+			close(started)
+			<-release
+			return 0, nil
+		})
+		require.NoError(t, sched.ScheduleJob(quartz.NewJobDetail(runJob, quartz.NewJobKey("run")), quartz.NewRunOnceTrigger(0)))
+		<-started
+
+		s := newScheduler(sched, &MockEnv{})
+		s.config = &Config{shutdownTimeout: timeout}
+		return s, s.shutdown()
+	}
+
+	t.Run("waits for run in progress", func(t *testing.T) {
+		release := make(chan struct{})
+		time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+		s, err := run(t, time.Second, release)
+		assert.NoError(t, err)
+		assert.NoError(t, s.runs.Err())
+	})
+
+	t.Run("gives up after timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		s, err := run(t, 50*time.Millisecond, release)
+		assert.EqualError(t, err, "scheduled run still in progress after shutdown timeout of 50ms")
+		// Requests of the run in progress are cancelled.
+		assert.ErrorIs(t, s.runs.Err(), context.Canceled)
+	})
+}
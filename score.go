@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMilestoneHorizonDays = 14
+
+// scoreMergeRequests attaches a priority score to every merge request.
+func scoreMergeRequests(mrs []*MergeRequestWithApprovals, weights ConfigPriorityWeights, now time.Time) {
+	for _, mr := range mrs {
+		mr.Score = scoreMergeRequest(mr, weights, now)
+	}
+}
+
+// scoreMergeRequest calculates a weighted priority score, higher is more urgent:
+//   - age: per day since the merge request was created;
+//   - size: per changed file;
+//   - approvals_missing: per approval still required;
+//   - milestone_due: per day the milestone due date is closer than the horizon,
+//     so overdue milestones keep increasing the score;
+//   - labels: flat weight for each matching label.
+func scoreMergeRequest(mr *MergeRequestWithApprovals, weights ConfigPriorityWeights, now time.Time) float64 {
+	m := mr.MergeRequest
+	var score float64
+
+	if m.CreatedAt != nil {
+		score += weights.Age * now.Sub(*m.CreatedAt).Hours() / 24
+	}
+
+	score += weights.Size * float64(changesCount(m.ChangesCount))
+	score += weights.ApprovalsMissing * float64(max(mr.ApprovalsLeft, 0))
+
+	if m.Milestone != nil && m.Milestone.DueDate != nil {
+		horizon := weights.MilestoneHorizonDays
+		if horizon == 0 {
+			horizon = defaultMilestoneHorizonDays
+		}
+		daysLeft := time.Time(*m.Milestone.DueDate).Sub(now).Hours() / 24
+		score += weights.MilestoneDue * math.Max(float64(horizon)-daysLeft, 0)
+	}
+
+	for _, label := range m.Labels {
+		score += weights.Labels[label]
+	}
+
+	return score
+}
+
+// changesCount parses the number of changed files as reported by GitLab,
+// which caps large diffs with a suffix, e.g. "1000+".
+func changesCount(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "+"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// limitMergeRequests keeps at most top merge requests with the highest score,
+// in their original order, and returns the number of omitted ones. Merge
+// requests with equal scores are kept in their original order. Non-positive
+// top keeps all of them.
+func limitMergeRequests(mrs []*MergeRequestWithApprovals, top int) ([]*MergeRequestWithApprovals, int) {
+	if top <= 0 || len(mrs) <= top {
+		return mrs, 0
+	}
+
+	byScore := make([]int, len(mrs))
+	for i := range byScore {
+		byScore[i] = i
+	}
+	sort.SliceStable(byScore, func(i, j int) bool {
+		return mrs[byScore[i]].Score > mrs[byScore[j]].Score
+	})
+
+	kept := byScore[:top]
+	sort.Ints(kept)
+
+	limited := make([]*MergeRequestWithApprovals, top)
+	for i, index := range kept {
+		limited[i] = mrs[index]
+	}
+	return limited, len(mrs) - top
+}
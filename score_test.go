@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestScoreMergeRequest(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	createdAt := now.AddDate(0, 0, -4)
+	dueDate := gitlab.ISOTime(now.AddDate(0, 0, 4))
+
+	mr := &MergeRequestWithApprovals{
+		MergeRequest: &gitlab.MergeRequest{
+			CreatedAt:    &createdAt,
+			ChangesCount: "12",
+			Labels:       gitlab.Labels{"priority::high", "backend"},
+			Milestone:    &gitlab.Milestone{DueDate: &dueDate},
+		},
+		ApprovalsLeft: 2,
+	}
+
+	testCases := []struct {
+		name     string
+		weights  ConfigPriorityWeights
+		expected float64
+	}{
+		{name: "no weights", weights: ConfigPriorityWeights{}, expected: 0},
+		{name: "age", weights: ConfigPriorityWeights{Age: 1.5}, expected: 6},
+		{name: "size", weights: ConfigPriorityWeights{Size: 0.5}, expected: 6},
+		{name: "approvals missing", weights: ConfigPriorityWeights{ApprovalsMissing: 3}, expected: 6},
+		{name: "milestone due with default horizon", weights: ConfigPriorityWeights{MilestoneDue: 1}, expected: 10},
+		{name: "milestone due with custom horizon", weights: ConfigPriorityWeights{MilestoneDue: 2, MilestoneHorizonDays: 3}, expected: 0},
+		{
+			name:     "labels",
+			weights:  ConfigPriorityWeights{Labels: map[string]float64{"priority::high": 10, "priority::low": -10}},
+			expected: 10,
+		},
+		{
+			name:     "combined",
+			weights:  ConfigPriorityWeights{Age: 1, Size: 1, ApprovalsMissing: 1},
+			expected: 18,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, scoreMergeRequest(mr, tc.weights, now), 0.0001)
+		})
+	}
+}
+
+func TestChangesCount(t *testing.T) {
+	assert.Equal(t, 0, changesCount(""))
+	assert.Equal(t, 5, changesCount("5"))
+	assert.Equal(t, 1000, changesCount("1000+"))
+}
+
+func TestFormatMergeRequestsSummary_Top(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	var mrs []*MergeRequestWithApprovals
+	for i := 1; i <= 3; i++ {
+		mrs = append(mrs, &MergeRequestWithApprovals{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: i, Title: "MR", WebURL: "https://gitlab.com/mr", CreatedAt: &createdAt,
+				Author: &gitlab.BasicUser{Name: "John Doe"}, BlockingDiscussionsResolved: true,
+			},
+		})
+	}
+
+	config := &Config{}
+	config.Priority.Top = 1
+	config.Priority.MoreURL = "https://gitlab.com/dashboard/merge_requests"
+
+	summary, err := formatMergeRequestsSummary(mrs, config, createdAt)
+	require.NoError(t, err)
+
+	assert.Contains(t, summary, "*Needs review (1)*")
+	assert.Contains(t, summary, "<https://gitlab.com/dashboard/merge_requests|...and 2 more>\n")
+}
+
+func TestLimitMergeRequests(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	mrs := []*MergeRequestWithApprovals{
+		{MergeRequest: &gitlab.MergeRequest{IID: 1, CreatedAt: day(3)}, Score: 1},
+		{MergeRequest: &gitlab.MergeRequest{IID: 2, CreatedAt: day(1)}, Score: 2},
+		{MergeRequest: &gitlab.MergeRequest{IID: 3, CreatedAt: day(2)}, Score: 8},
+		{MergeRequest: &gitlab.MergeRequest{IID: 4, CreatedAt: day(4)}, Score: 5},
+	}
+
+	// The most urgent merge requests are kept in the configured order, not the oldest ones.
+	limited, omitted := limitMergeRequests(sortMergeRequests(mrs, SortByAge), 2)
+	assert.Equal(t, 2, omitted)
+	require.Len(t, limited, 2)
+	assert.Equal(t, 3, limited[0].MergeRequest.IID)
+	assert.Equal(t, 4, limited[1].MergeRequest.IID)
+
+	limited, omitted = limitMergeRequests(mrs, 0)
+	assert.Equal(t, 0, omitted)
+	assert.Len(t, limited, 4)
+}
@@ -1,23 +1,71 @@
 package main
 
-import "github.com/slack-go/slack"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/slack-go/slack"
+)
 
 //go:generate mockery --name SlackClient
 type SlackClient interface {
-	PostWebhook(payload *slack.WebhookMessage) error
+	PostWebhook(ctx context.Context, payload *slack.WebhookMessage) error
 }
 
 type slackClient struct {
 	webhookURL string
 }
 
-func (c *slackClient) PostWebhook(payload *slack.WebhookMessage) error {
-	return slack.PostWebhook(c.webhookURL, payload)
+func (c *slackClient) PostWebhook(ctx context.Context, payload *slack.WebhookMessage) error {
+	return slack.PostWebhookContext(ctx, c.webhookURL, payload)
+}
+
+// dryRunSlackClient writes webhook payloads as JSON to a file instead of posting them.
+// An empty path means stdout.
+type dryRunSlackClient struct {
+	path string
+}
+
+func (c *dryRunSlackClient) PostWebhook(_ context.Context, payload *slack.WebhookMessage) error {
+	return writeDryRunPayload(c.path, payload)
+}
+
+func newSlackClient(config *Config) SlackClient {
+	if config.DryRun {
+		return &dryRunSlackClient{path: config.DryRunOutput}
+	}
+	return &slackClient{webhookURL: config.Slack.WebhookURL}
+}
+
+// writeDryRunPayload appends the JSON encoded payload to the file at path, or writes it to stdout if path is empty.
+func writeDryRunPayload(path string, payload any) error {
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding payload: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
-func sendSlackMessage(client SlackClient, message string) error {
+func sendSlackMessage(ctx context.Context, client SlackClient, message string) error {
 	msg := slack.WebhookMessage{
 		Text: message,
 	}
-	return client.PostWebhook(&msg)
+	return client.PostWebhook(ctx, &msg)
 }
@@ -1,14 +1,43 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/flexoid/mergentle-reminder/mocks"
 	slack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSendSlackMessage(t *testing.T) {
 	mockSlackClient := mocks.NewSlackClient(t)
-	mockSlackClient.EXPECT().PostWebhook(&slack.WebhookMessage{Text: "hello"}).Return(nil)
-	sendSlackMessage(mockSlackClient, "hello")
+	mockSlackClient.EXPECT().PostWebhook(mock.Anything, &slack.WebhookMessage{Text: "hello"}).Return(nil)
+	sendSlackMessage(context.Background(), mockSlackClient, "hello")
+}
+
+func TestDryRunSlackClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payloads.json")
+	client := &dryRunSlackClient{path: path}
+
+	require.NoError(t, sendSlackMessage(context.Background(), client, "first"))
+	require.NoError(t, sendSlackMessage(context.Background(), client, "second"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	payload := "{\n  \"text\": \"%s\",\n  \"replace_original\": false,\n  \"delete_original\": false\n}\n"
+	assert.Equal(t, fmt.Sprintf(payload, "first")+fmt.Sprintf(payload, "second"), string(data))
+}
+
+func TestNewSlackClient(t *testing.T) {
+	config := &Config{}
+	config.Slack.WebhookURL = "https://hooks.slack.com/services/xxx"
+	assert.IsType(t, &slackClient{}, newSlackClient(config))
+
+	config.DryRun = true
+	assert.IsType(t, &dryRunSlackClient{}, newSlackClient(config))
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SortByAge              = "age"
+	SortByUpdated          = "updated"
+	SortByProject          = "project"
+	SortByAuthor           = "author"
+	SortByApprovalsMissing = "approvals_missing"
+	SortByPriority         = "priority"
+
+	GroupByNone     = "none"
+	GroupByProject  = "project"
+	GroupByAuthor   = "author"
+	GroupByReviewer = "reviewer"
+
+	// noReviewerGroup is the name of the group of merge requests without reviewers.
+	noReviewerGroup = "No reviewer"
+)
+
+var sortByOptions = []string{SortByAge, SortByUpdated, SortByProject, SortByAuthor, SortByApprovalsMissing, SortByPriority}
+
+var groupByOptions = []string{GroupByNone, GroupByProject, GroupByAuthor, GroupByReviewer}
+
+// MergeRequestGroup is a named subset of merge requests rendered under its own header.
+type MergeRequestGroup struct {
+	Name          string
+	MergeRequests []*MergeRequestWithApprovals
+}
+
+// sortMergeRequests returns a copy of mrs ordered by the given key.
+// An empty key keeps the original order.
+func sortMergeRequests(mrs []*MergeRequestWithApprovals, sortBy string) []*MergeRequestWithApprovals {
+	sorted := make([]*MergeRequestWithApprovals, len(mrs))
+	copy(sorted, mrs)
+
+	var less func(a, b *MergeRequestWithApprovals) bool
+	switch sortBy {
+	case SortByAge:
+		// Oldest first.
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			return timeBefore(a.MergeRequest.CreatedAt, b.MergeRequest.CreatedAt)
+		}
+	case SortByUpdated:
+		// Least recently updated first.
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			return timeBefore(a.MergeRequest.UpdatedAt, b.MergeRequest.UpdatedAt)
+		}
+	case SortByProject:
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			if a.ProjectName != b.ProjectName {
+				return a.ProjectName < b.ProjectName
+			}
+			return a.MergeRequest.IID < b.MergeRequest.IID
+		}
+	case SortByAuthor:
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			return strings.ToLower(authorName(a)) < strings.ToLower(authorName(b))
+		}
+	case SortByApprovalsMissing:
+		// Most approvals missing first.
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			return a.ApprovalsLeft > b.ApprovalsLeft
+		}
+	case SortByPriority:
+		// Highest score first.
+		less = func(a, b *MergeRequestWithApprovals) bool {
+			return a.Score > b.Score
+		}
+	default:
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
+// groupMergeRequests splits merge requests into groups by the given key,
+// keeping groups in order of first appearance. A merge request with several
+// reviewers appears in the group of each of them.
+func groupMergeRequests(mrs []*MergeRequestWithApprovals, groupBy string) []*MergeRequestGroup {
+	var groups []*MergeRequestGroup
+	index := make(map[string]*MergeRequestGroup)
+
+	add := func(name string, mr *MergeRequestWithApprovals) {
+		group, ok := index[name]
+		if !ok {
+			group = &MergeRequestGroup{Name: name}
+			index[name] = group
+			groups = append(groups, group)
+		}
+		group.MergeRequests = append(group.MergeRequests, mr)
+	}
+
+	for _, mr := range mrs {
+		switch groupBy {
+		case GroupByProject:
+			add(mr.ProjectName, mr)
+		case GroupByAuthor:
+			add(authorName(mr), mr)
+		case GroupByReviewer:
+			if len(mr.MergeRequest.Reviewers) == 0 {
+				add(noReviewerGroup, mr)
+			}
+			for _, reviewer := range mr.MergeRequest.Reviewers {
+				add(reviewer.Name, mr)
+			}
+		default:
+			add("", mr)
+		}
+	}
+
+	return groups
+}
+
+func validateSortAndGroup(sortBy, groupBy string) error {
+	if sortBy != "" && !slices.Contains(sortByOptions, sortBy) {
+		return fmt.Errorf("invalid sort_by %q, must be one of: %s", sortBy, strings.Join(sortByOptions, ", "))
+	}
+	if groupBy != "" && !slices.Contains(groupByOptions, groupBy) {
+		return fmt.Errorf("invalid group_by %q, must be one of: %s", groupBy, strings.Join(groupByOptions, ", "))
+	}
+	return nil
+}
+
+func authorName(mr *MergeRequestWithApprovals) string {
+	if mr.MergeRequest.Author == nil {
+		return ""
+	}
+	return mr.MergeRequest.Author.Name
+}
+
+// timeBefore orders nil timestamps last.
+func timeBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func testMergeRequestsForSorting() []*MergeRequestWithApprovals {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	return []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 1, CreatedAt: day(2), UpdatedAt: day(5),
+				Author:    &gitlab.BasicUser{Name: "Zed"},
+				Reviewers: []*gitlab.BasicUser{{Name: "Alice"}, {Name: "Bob"}},
+			},
+			ProjectName:   "group/beta",
+			ApprovalsLeft: 1,
+		},
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 2, CreatedAt: day(1), UpdatedAt: day(9),
+				Author: &gitlab.BasicUser{Name: "adam"},
+			},
+			ProjectName:   "group/alpha",
+			ApprovalsLeft: 0,
+		},
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 3, CreatedAt: day(3), UpdatedAt: day(4),
+				Author:    &gitlab.BasicUser{Name: "Mia"},
+				Reviewers: []*gitlab.BasicUser{{Name: "Bob"}},
+			},
+			ProjectName:   "group/beta",
+			ApprovalsLeft: 2,
+		},
+	}
+}
+
+func iids(mrs []*MergeRequestWithApprovals) []int {
+	var ids []int
+	for _, mr := range mrs {
+		ids = append(ids, mr.MergeRequest.IID)
+	}
+	return ids
+}
+
+func TestSortMergeRequests(t *testing.T) {
+	testCases := []struct {
+		sortBy   string
+		expected []int
+	}{
+		{sortBy: "", expected: []int{1, 2, 3}},
+		{sortBy: SortByAge, expected: []int{2, 1, 3}},
+		{sortBy: SortByUpdated, expected: []int{3, 1, 2}},
+		{sortBy: SortByProject, expected: []int{2, 1, 3}},
+		{sortBy: SortByAuthor, expected: []int{2, 3, 1}},
+		{sortBy: SortByApprovalsMissing, expected: []int{3, 1, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sortBy, func(t *testing.T) {
+			mrs := testMergeRequestsForSorting()
+			sorted := sortMergeRequests(mrs, tc.sortBy)
+
+			assert.Equal(t, tc.expected, iids(sorted))
+			assert.Equal(t, []int{1, 2, 3}, iids(mrs), "input must not be modified")
+		})
+	}
+}
+
+func TestGroupMergeRequests(t *testing.T) {
+	t.Run("by project", func(t *testing.T) {
+		groups := groupMergeRequests(testMergeRequestsForSorting(), GroupByProject)
+
+		require.Len(t, groups, 2)
+		assert.Equal(t, "group/beta", groups[0].Name)
+		assert.Equal(t, []int{1, 3}, iids(groups[0].MergeRequests))
+		assert.Equal(t, "group/alpha", groups[1].Name)
+		assert.Equal(t, []int{2}, iids(groups[1].MergeRequests))
+	})
+
+	t.Run("by reviewer", func(t *testing.T) {
+		groups := groupMergeRequests(testMergeRequestsForSorting(), GroupByReviewer)
+
+		require.Len(t, groups, 3)
+		assert.Equal(t, "Alice", groups[0].Name)
+		assert.Equal(t, []int{1}, iids(groups[0].MergeRequests))
+		assert.Equal(t, "Bob", groups[1].Name)
+		assert.Equal(t, []int{1, 3}, iids(groups[1].MergeRequests))
+		assert.Equal(t, "No reviewer", groups[2].Name)
+		assert.Equal(t, []int{2}, iids(groups[2].MergeRequests))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		groups := groupMergeRequests(testMergeRequestsForSorting(), GroupByNone)
+
+		require.Len(t, groups, 1)
+		assert.Equal(t, "", groups[0].Name)
+		assert.Equal(t, []int{1, 2, 3}, iids(groups[0].MergeRequests))
+	})
+}
+
+func TestValidateSortAndGroup(t *testing.T) {
+	assert.NoError(t, validateSortAndGroup("", ""))
+	assert.NoError(t, validateSortAndGroup(SortByAge, GroupByProject))
+	assert.ErrorContains(t, validateSortAndGroup("size", ""), "invalid sort_by")
+	assert.ErrorContains(t, validateSortAndGroup("", "label"), "invalid group_by")
+}
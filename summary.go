@@ -0,0 +1,184 @@
+package main
+
+import (
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Summary is the data model the merge request digest is rendered from.
+// It is passed as is to user-defined templates, so field names are part of
+// the public configuration interface.
+type Summary struct {
+	// Sections holds non-empty sections in rendering order.
+	Sections []*SummarySection
+	// MergeRequests holds all rendered merge requests regardless of section.
+	MergeRequests []*MergeRequestView
+	// Omitted is the number of merge requests left out because of priority.top.
+	Omitted int
+	// MoreURL points to the full list of merge requests, may be empty.
+	MoreURL string
+	// Now is the time the summary was generated at.
+	Now time.Time
+	// RelativeDates is set when dates should be rendered relative to Now.
+	RelativeDates bool
+	// Language is the language code the summary is rendered in.
+	Language string
+
+	// businessDays makes relative dates count only working days.
+	businessDays bool
+	catalog      *Catalog
+}
+
+type SummarySection struct {
+	State  MergeRequestState
+	Title  string
+	Count  int
+	Groups []*SummaryGroup
+}
+
+// SummaryGroup is a group of merge requests within a section. Name is empty
+// when grouping is disabled.
+type SummaryGroup struct {
+	Name          string
+	MergeRequests []*MergeRequestView
+}
+
+// MergeRequestView is a flattened representation of a merge request.
+type MergeRequestView struct {
+	IID                      int
+	Title                    string
+	URL                      string
+	Project                  string
+	Author                   string
+	AuthorUsername           string
+	Reviewers                []string
+	Labels                   []string
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+	ApprovedBy               []string
+	ApprovalsLeft            int
+	Score                    float64
+	State                    string
+	HasUnresolvedDiscussions bool
+	// AuthorAway and AwayReviewers mark out-of-office users, see out_of_office in the configuration.
+	AuthorAway        bool
+	AwayReviewers     []string
+	SuggestedReviewer string
+
+	// MergeRequest is the raw GitLab merge request for anything not covered above.
+	MergeRequest *gitlab.MergeRequest
+}
+
+// ByProject returns all merge requests grouped by project, in order of first appearance.
+func (s *Summary) ByProject() []*SummaryGroup {
+	var groups []*SummaryGroup
+	index := make(map[string]*SummaryGroup)
+
+	for _, mr := range s.MergeRequests {
+		group, ok := index[mr.Project]
+		if !ok {
+			group = &SummaryGroup{Name: mr.Project}
+			index[mr.Project] = group
+			groups = append(groups, group)
+		}
+		group.MergeRequests = append(group.MergeRequests, mr)
+	}
+
+	return groups
+}
+
+func buildSummary(mrs []*MergeRequestWithApprovals, config *Config, catalog *Catalog, now time.Time) *Summary {
+	mrs, omitted := limitMergeRequests(mrs, config.Priority.Top)
+
+	if config.location != nil {
+		now = now.In(config.location)
+	}
+
+	summary := &Summary{
+		Omitted:       omitted,
+		MoreURL:       config.Priority.MoreURL,
+		Now:           now,
+		RelativeDates: config.RelativeDates,
+		Language:      catalog.Language,
+		businessDays:  config.BusinessDays,
+		catalog:       catalog,
+	}
+
+	views := make(map[*MergeRequestWithApprovals]*MergeRequestView, len(mrs))
+	for _, mr := range mrs {
+		view := newMergeRequestView(mr, catalog, config.location)
+		views[mr] = view
+		summary.MergeRequests = append(summary.MergeRequests, view)
+	}
+
+	buckets := classifyMergeRequests(mrs)
+	for _, state := range mergeRequestStates {
+		if len(buckets[state]) == 0 {
+			continue
+		}
+
+		section := &SummarySection{
+			State: state,
+			Title: catalog.T(state.MessageKey()),
+			Count: len(buckets[state]),
+		}
+
+		for _, group := range groupMergeRequests(buckets[state], config.GroupBy) {
+			summaryGroup := &SummaryGroup{Name: group.Name}
+			if config.GroupBy == GroupByReviewer && group.Name == noReviewerGroup {
+				summaryGroup.Name = catalog.T("no_reviewer")
+			}
+			for _, mr := range group.MergeRequests {
+				summaryGroup.MergeRequests = append(summaryGroup.MergeRequests, views[mr])
+			}
+			section.Groups = append(section.Groups, summaryGroup)
+		}
+
+		summary.Sections = append(summary.Sections, section)
+	}
+
+	return summary
+}
+
+// newMergeRequestView flattens a merge request, converting its dates to loc unless it is nil.
+func newMergeRequestView(mr *MergeRequestWithApprovals, catalog *Catalog, loc *time.Location) *MergeRequestView {
+	m := mr.MergeRequest
+
+	view := &MergeRequestView{
+		IID:                      m.IID,
+		Title:                    m.Title,
+		URL:                      m.WebURL,
+		Project:                  mr.ProjectName,
+		Author:                   authorName(mr),
+		Labels:                   m.Labels,
+		ApprovedBy:               mr.ApprovedBy,
+		ApprovalsLeft:            mr.ApprovalsLeft,
+		Score:                    mr.Score,
+		State:                    catalog.T(classifyMergeRequest(mr).MessageKey()),
+		HasUnresolvedDiscussions: !m.BlockingDiscussionsResolved,
+		AuthorAway:               mr.AuthorAway,
+		AwayReviewers:            mr.AwayReviewers,
+		SuggestedReviewer:        mr.SuggestedReviewer,
+		MergeRequest:             m,
+	}
+
+	if m.Author != nil {
+		view.AuthorUsername = m.Author.Username
+	}
+	for _, reviewer := range m.Reviewers {
+		view.Reviewers = append(view.Reviewers, reviewer.Name)
+	}
+	if m.CreatedAt != nil {
+		view.CreatedAt = *m.CreatedAt
+	}
+	if m.UpdatedAt != nil {
+		view.UpdatedAt = *m.UpdatedAt
+	}
+	if loc != nil {
+		view.CreatedAt = view.CreatedAt.In(loc)
+		view.UpdatedAt = view.UpdatedAt.In(loc)
+	}
+
+	return view
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTelegramAPIURL = "https://api.telegram.org"
+	// telegramMaxMessageRunes is the length limit of a message text after entities parsing.
+	telegramMaxMessageRunes = 4096
+)
+
+var telegramMarkdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+var telegramURLReplacer = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+
+// escapeTelegramMarkdown escapes text for Telegram MarkdownV2, see
+// https://core.telegram.org/bots/api#markdownv2-style.
+func escapeTelegramMarkdown(s string) string {
+	return telegramMarkdownReplacer.Replace(s)
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// telegramDestination sends the summary with the Telegram Bot API, split into
+// as many messages as needed to stay within the message length limit.
+type telegramDestination struct {
+	config *Config
+}
+
+func (d *telegramDestination) Name() string {
+	return "Telegram"
+}
+
+func (d *telegramDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	cfg := d.config.Telegram
+
+	catalog, err := catalogFor(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	blocks := telegramBlocks(buildSummary(mrs, d.config, catalog, now))
+
+	sizes := make([]int, len(blocks))
+	for i, block := range blocks {
+		sizes[i] = runeCount(block)
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+	endpoint := strings.TrimSuffix(apiURL, "/") + "/bot" + cfg.BotToken + "/sendMessage"
+
+	for _, chunk := range splitBlocks(sizes, telegramMaxMessageRunes) {
+		msg := &telegramMessage{
+			ChatID:                cfg.ChatID,
+			Text:                  strings.Join(blocks[chunk[0]:chunk[1]], "\n"),
+			ParseMode:             "MarkdownV2",
+			DisableWebPagePreview: true,
+		}
+
+		if d.config.DryRun {
+			if err := writeDryRunPayload(d.config.DryRunOutput, msg); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := postJSON(ctx, endpoint, msg); err != nil {
+			// Errors of the HTTP client include the URL, which contains the bot token.
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return fmt.Errorf("error sending Telegram message: %w", err)
+		}
+	}
+
+	if !d.config.DryRun {
+		log.Println("Successfully sent merge request summary to Telegram.")
+	}
+	return nil
+}
+
+// telegramBlocks renders the summary as MarkdownV2 blocks, one per merge
+// request. Section and group headers are prepended to the first merge request
+// below them so they are never separated by message splitting. Titles are
+// shortened when a block would not fit in a single message.
+func telegramBlocks(summary *Summary) []string {
+	var blocks []string
+
+	for _, section := range summary.Sections {
+		header := fmt.Sprintf("*%s*\n", escapeTelegramMarkdown(fmt.Sprintf("%s (%d)", section.Title, section.Count)))
+		for _, group := range section.Groups {
+			if group.Name != "" {
+				header += fmt.Sprintf("_%s_\n", escapeTelegramMarkdown(group.Name))
+			}
+			for _, mr := range group.MergeRequests {
+				details := mergeRequestDetails(summary, mr)
+				render := func(title string) string {
+					var sb strings.Builder
+					sb.WriteString(header)
+					fmt.Fprintf(&sb, "▶️ [%s](%s)\n",
+						escapeTelegramMarkdown(fmt.Sprintf("!%d %s", mr.IID, title)), telegramURLReplacer.Replace(mr.URL))
+					for _, detail := range details {
+						sb.WriteString(escapeTelegramMarkdown(detail) + "\n")
+					}
+					return sb.String()
+				}
+
+				blocks = append(blocks, fitTitle(mr.Title, telegramMaxMessageRunes, runeCount, render))
+				header = ""
+			}
+		}
+	}
+
+	if summary.Omitted > 0 {
+		text := escapeTelegramMarkdown(summary.catalog.T("and_more", summary.Omitted))
+		if summary.MoreURL != "" {
+			text = fmt.Sprintf("[%s](%s)", text, telegramURLReplacer.Replace(summary.MoreURL))
+		}
+		blocks = append(blocks, text)
+	}
+
+	return blocks
+}
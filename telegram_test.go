@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+// manyMergeRequests returns n merge requests needing review with long titles.
+func manyMergeRequests(n int) []*MergeRequestWithApprovals {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	var mrs []*MergeRequestWithApprovals
+	for i := 1; i <= n; i++ {
+		mrs = append(mrs, &MergeRequestWithApprovals{
+			MergeRequest: &gitlab.MergeRequest{
+				IID:                         i,
+				Title:                       fmt.Sprintf("Change %d: %s", i, strings.Repeat("refactor things. ", 10)),
+				WebURL:                      fmt.Sprintf("https://gitlab.com/group/project/-/merge_requests/%d", i),
+				CreatedAt:                   &createdAt,
+				BlockingDiscussionsResolved: true,
+				Author:                      &gitlab.BasicUser{Name: "John Doe"},
+			},
+			ProjectName: "group/project",
+		})
+	}
+	return mrs
+}
+
+func TestTelegramDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	var messages []telegramMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bot123:abc/sendMessage", r.URL.Path)
+
+		var msg telegramMessage
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &msg))
+		messages = append(messages, msg)
+
+		io.WriteString(w, `{"ok": true}`)
+	}))
+	defer server.Close()
+
+	config := &Config{Telegram: ConfigTelegram{BotToken: "123:abc", ChatID: "-100123", APIURL: server.URL}}
+
+	t.Run("single message", func(t *testing.T) {
+		messages = nil
+		require.NoError(t, (&telegramDestination{config: config}).Send(context.Background(), testMergeRequestsForExport(), now))
+
+		require.Len(t, messages, 1)
+		assert.Equal(t, "-100123", messages[0].ChatID)
+		assert.Equal(t, "MarkdownV2", messages[0].ParseMode)
+		assert.True(t, strings.HasPrefix(messages[0].Text,
+			"*Needs review \\(1\\)*\n▶️ [\\!12 Add \"export\", finally](https://gitlab.com/group/alpha/-/merge_requests/12)\nAuthor: John Doe\n"),
+			messages[0].Text)
+	})
+
+	t.Run("splits long summary", func(t *testing.T) {
+		messages = nil
+		require.NoError(t, (&telegramDestination{config: config}).Send(context.Background(), manyMergeRequests(60), now))
+
+		require.Greater(t, len(messages), 1)
+		var text string
+		for _, msg := range messages {
+			assert.LessOrEqual(t, len([]rune(msg.Text)), telegramMaxMessageRunes)
+			text += msg.Text
+		}
+		assert.Equal(t, 1, strings.Count(text, "Needs review"))
+		assert.Equal(t, 60, strings.Count(text, "▶️"))
+	})
+
+	t.Run("shortens block over the limit", func(t *testing.T) {
+		messages = nil
+		mrs := manyMergeRequests(1)
+		mrs[0].MergeRequest.Title = strings.Repeat("long.", 1000)
+		require.NoError(t, (&telegramDestination{config: config}).Send(context.Background(), mrs, now))
+
+		require.Len(t, messages, 1)
+		assert.LessOrEqual(t, len([]rune(messages[0].Text)), telegramMaxMessageRunes)
+		assert.Contains(t, messages[0].Text, "…](https://gitlab.com/group/project/-/merge_requests/1)\nAuthor: John Doe\n")
+	})
+
+	t.Run("error does not leak token", func(t *testing.T) {
+		config := &Config{Telegram: ConfigTelegram{BotToken: "123:abc", ChatID: "1", APIURL: "http://127.0.0.1:0"}}
+		err := (&telegramDestination{config: config}).Send(context.Background(), testMergeRequestsForExport(), now)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "123:abc")
+	})
+}
+
+func TestEscapeTelegramMarkdown(t *testing.T) {
+	assert.Equal(t, `fix\(api\): 1\.5 \- \*done\*\!`, escapeTelegramMarkdown("fix(api): 1.5 - *done*!"))
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// defaultSummaryTemplate renders the Slack digest when no custom template is configured.
+const defaultSummaryTemplate = `
+{{- range .Sections -}}
+*{{ .Title }} ({{ .Count }})*
+
+{{ range .Groups -}}
+{{ if .Name }}:file_folder: _{{ .Name }}_
+{{ end -}}
+{{ range .MergeRequests -}}
+:arrow_forward: <{{ .URL }}|{{ .Title }}>
+*{{ t "author" }}:* {{ .Author }}
+{{ if $.RelativeDates }}*{{ t "opened" }}:* {{ t "ago" (humanizeAge .CreatedAt) }}{{ else }}*{{ t "created_at" }}:* {{ formatDate .CreatedAt (t "date_layout") }}{{ end }}
+*{{ t "approved_by" }}:* {{ if .ApprovedBy }}{{ join .ApprovedBy ", " }}{{ else }}{{ t "none" }}{{ end }}
+{{ if .HasUnresolvedDiscussions }}*{{ t "extra" }}:* :warning: {{ t "unresolved_discussions" }}
+{{ end }}{{ if .AuthorAway }}:palm_tree: {{ t "author_away" }}
+{{ end }}{{ if .AwayReviewers }}:palm_tree: {{ t "reviewer_away" (join .AwayReviewers ", ") }}{{ if .SuggestedReviewer }}. {{ t "suggested_reviewer" .SuggestedReviewer }}{{ end }}
+{{ end }}
+{{ end -}}
+{{ end -}}
+{{ end -}}
+{{ if .Omitted -}}
+{{ if .MoreURL }}<{{ .MoreURL }}|{{ t "and_more" .Omitted }}>{{ else }}{{ t "and_more" .Omitted }}{{ end }}
+{{ end -}}
+`
+
+var defaultTemplate = template.Must(parseSummaryTemplate("default", defaultSummaryTemplate))
+
+// templateFuncs returns helper functions available in summary templates.
+// Helpers depending on the current time are bound to now, translated ones to catalog.
+func templateFuncs(now time.Time, businessDays bool, catalog *Catalog) template.FuncMap {
+	return template.FuncMap{
+		"t":      catalog.T,
+		"plural": catalog.N,
+		"join": func(values []string, sep string) string {
+			return strings.Join(values, sep)
+		},
+		"mention": func(username string) string {
+			if username == "" {
+				return ""
+			}
+			return "@" + username
+		},
+		"formatDate": func(t time.Time, layout string) string {
+			return t.Format(layout)
+		},
+		"humanizeAge": func(t time.Time) string {
+			return humanizeAge(t, now, businessDays, catalog)
+		},
+		"ageDays": func(t time.Time) int {
+			return int(now.Sub(t).Hours() / 24)
+		},
+	}
+}
+
+func parseSummaryTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs(time.Time{}, false, catalogs[defaultLanguage])).Parse(text)
+}
+
+// loadSummaryTemplate parses the template configured either inline or as a file.
+// It returns nil when neither is configured.
+func loadSummaryTemplate(config *Config) (*template.Template, error) {
+	if config.Template != "" && config.TemplateFile != "" {
+		return nil, fmt.Errorf("template and template_file are mutually exclusive")
+	}
+
+	name, text := "template", config.Template
+	if config.TemplateFile != "" {
+		data, err := os.ReadFile(config.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading template file: %w", err)
+		}
+		name, text = config.TemplateFile, string(data)
+	}
+
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := parseSummaryTemplate(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl = tmpl.Option("missingkey=error")
+	if err := checkSummaryTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// checkSummaryTemplate renders the template with sample summaries, so that
+// references to unknown fields are reported when the template is loaded
+// instead of on the first run.
+func checkSummaryTemplate(tmpl *template.Template) error {
+	for _, summary := range sampleSummaries() {
+		if _, err := renderSummaryTemplate(tmpl, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleSummaries returns summaries with every field of a merge request set,
+// with absolute and relative dates.
+func sampleSummaries() []*Summary {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	createdAt := now.AddDate(0, 0, -4)
+
+	mrs := []*MergeRequestWithApprovals{{
+		MergeRequest: &gitlab.MergeRequest{
+			IID:       1,
+			Title:     "Sample merge request",
+			WebURL:    "https://gitlab.example.com/group/project/-/merge_requests/1",
+			Labels:    gitlab.Labels{"backend"},
+			CreatedAt: &createdAt,
+			UpdatedAt: &createdAt,
+			Author:    &gitlab.BasicUser{ID: 1, Username: "janedoe", Name: "Jane Doe"},
+			Reviewers: []*gitlab.BasicUser{{ID: 2, Username: "johndoe", Name: "John Doe"}},
+		},
+		ProjectName:       "group/project",
+		ApprovedBy:        []string{"James Doe"},
+		ApprovalsLeft:     1,
+		Score:             1,
+		AuthorAway:        true,
+		AwayReviewers:     []string{"John Doe"},
+		SuggestedReviewer: "James Doe",
+	}}
+
+	var summaries []*Summary
+	for _, relativeDates := range []bool{false, true} {
+		config := &Config{GroupBy: GroupByProject, RelativeDates: relativeDates}
+		config.Priority.MoreURL = "https://gitlab.example.com/dashboard/merge_requests"
+
+		summary := buildSummary(mrs, config, catalogs[defaultLanguage], now)
+		summary.Omitted = 1
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func renderSummaryTemplate(tmpl *template.Template, summary *Summary) (string, error) {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Funcs(templateFuncs(summary.Now, summary.businessDays, summary.catalog)).Execute(&sb, summary); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
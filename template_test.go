@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestFormatMergeRequestsSummary_DefaultTemplate(t *testing.T) {
+	createdAt := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	mrs := []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				Title: "Fix it", WebURL: "https://gitlab.com/mr/1", CreatedAt: &createdAt,
+				Author: &gitlab.BasicUser{Name: "John Doe"},
+			},
+			ProjectName: "group/project",
+			ApprovedBy:  []string{"Jane Doe", "James Doe"},
+		},
+	}
+
+	config := &Config{GroupBy: GroupByProject}
+
+	summary, err := formatMergeRequestsSummary(mrs, config, createdAt)
+	require.NoError(t, err)
+
+	expected := "*Waiting on author (1)*\n\n" +
+		":file_folder: _group/project_\n" +
+		":arrow_forward: <https://gitlab.com/mr/1|Fix it>\n*Author:* John Doe\n*Created at:* 10 January 2024, 12:00 UTC\n*Approved by:* Jane Doe, James Doe\n" +
+		"*Extra:* :warning: Has unresolved blocking discussions\n\n"
+
+	assert.Equal(t, expected, summary)
+}
+
+func TestRenderSummaryTemplate_CustomTemplate(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	createdAt := now.AddDate(0, 0, -4)
+
+	mrs := []*MergeRequestWithApprovals{
+		{
+			MergeRequest: &gitlab.MergeRequest{
+				IID: 5, Title: "Add feature", CreatedAt: &createdAt, BlockingDiscussionsResolved: true,
+				Author: &gitlab.BasicUser{Name: "John Doe", Username: "johndoe"},
+			},
+			ProjectName:   "group/project",
+			ApprovedBy:    []string{"Jane Doe", "James Doe"},
+			ApprovalsLeft: 1,
+		},
+	}
+
+	tmpl, err := parseSummaryTemplate("test", `{{ range .MergeRequests }}{{ .Project }}!{{ .IID }} by {{ mention .AuthorUsername }}, opened {{ humanizeAge .CreatedAt }} ago ({{ ageDays .CreatedAt }}d), approved by {{ join .ApprovedBy " & " }} [{{ .State }}]{{ end }}`)
+	require.NoError(t, err)
+
+	summary, err := renderSummaryTemplate(tmpl, buildSummary(mrs, &Config{}, catalogs["en"], now))
+	require.NoError(t, err)
+
+	assert.Equal(t, "group/project!5 by @johndoe, opened 4 days ago (4d), approved by Jane Doe & James Doe [Needs review]", summary)
+}
+
+func TestLoadSummaryTemplate(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		tmpl, err := loadSummaryTemplate(&Config{})
+		assert.NoError(t, err)
+		assert.Nil(t, tmpl)
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		tmpl, err := loadSummaryTemplate(&Config{Template: "{{ len .MergeRequests }}"})
+		assert.NoError(t, err)
+		assert.NotNil(t, tmpl)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "summary.tmpl")
+		require.NoError(t, os.WriteFile(path, []byte("{{ .Omitted }}"), 0o644))
+
+		tmpl, err := loadSummaryTemplate(&Config{TemplateFile: path})
+		assert.NoError(t, err)
+		assert.NotNil(t, tmpl)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := loadSummaryTemplate(&Config{Template: "{{ range .MergeRequests }}"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := loadSummaryTemplate(&Config{Template: "{{ shout .Now }}"})
+		assert.ErrorContains(t, err, `function "shout" not defined`)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := loadSummaryTemplate(&Config{Template: "{{ .Foo }}"})
+		assert.ErrorContains(t, err, `can't evaluate field Foo`)
+
+		// Fields of merge requests are checked too.
+		_, err = loadSummaryTemplate(&Config{Template: "{{ range .MergeRequests }}{{ .Reviewer }}{{ end }}"})
+		assert.ErrorContains(t, err, `can't evaluate field Reviewer`)
+
+		_, err = loadSummaryTemplate(&Config{Template: "{{ if .RelativeDates }}{{ .Ago }}{{ end }}"})
+		assert.ErrorContains(t, err, `can't evaluate field Ago`)
+	})
+
+	t.Run("both inline and file", func(t *testing.T) {
+		_, err := loadSummaryTemplate(&Config{Template: "x", TemplateFile: "y"})
+		assert.ErrorContains(t, err, "mutually exclusive")
+	})
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	webhookEvent           = "merge_requests.digest"
+	webhookSignatureHeader = "X-Mergentle-Signature"
+	defaultWebhookRetries  = 3
+)
+
+// WebhookDocument describes a run and is posted as is, or passed to the
+// webhook template. Field names are part of the public configuration interface.
+type WebhookDocument struct {
+	Event         string                 `json:"event"`
+	SchemaVersion int                    `json:"schema_version"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Count         int                    `json:"count"`
+	States        map[string]int         `json:"states"`
+	MergeRequests []ExportedMergeRequest `json:"merge_requests"`
+}
+
+func newWebhookDocument(mrs []*MergeRequestWithApprovals, now time.Time) *WebhookDocument {
+	export := newExport(mrs, now)
+
+	doc := &WebhookDocument{
+		Event:         webhookEvent,
+		SchemaVersion: export.SchemaVersion,
+		GeneratedAt:   export.GeneratedAt,
+		Count:         len(export.MergeRequests),
+		States:        make(map[string]int, len(mergeRequestStates)),
+		MergeRequests: export.MergeRequests,
+	}
+
+	for _, state := range mergeRequestStates {
+		doc.States[state.MessageKey()] = 0
+	}
+	for _, mr := range export.MergeRequests {
+		doc.States[mr.State]++
+	}
+
+	return doc
+}
+
+// webhookRequest is the request written in dry run mode.
+type webhookRequest struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// webhookDestination posts the run document, or the body rendered from the
+// configured template, to an arbitrary HTTP endpoint.
+type webhookDestination struct {
+	config *Config
+	client *http.Client
+	// retryDelay is the delay before the first retry, doubled on every next one.
+	retryDelay time.Duration
+}
+
+func newWebhookDestination(config *Config) *webhookDestination {
+	return &webhookDestination{
+		config:     config,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		retryDelay: time.Second,
+	}
+}
+
+func (d *webhookDestination) Name() string {
+	return "webhook"
+}
+
+// redactHeaders returns a copy of headers with the values replaced, except
+// the content type. Values of configured headers and the signature are
+// secrets, and dry run output usually ends up in logs.
+func redactHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if !strings.EqualFold(name, "Content-Type") {
+			value = "[redacted]"
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+func (d *webhookDestination) Send(ctx context.Context, mrs []*MergeRequestWithApprovals, now time.Time) error {
+	cfg := d.config.Webhook
+
+	body, err := renderWebhookBody(d.config.webhookTemplate, newWebhookDocument(mrs, now), now)
+	if err != nil {
+		return fmt.Errorf("error rendering webhook body: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+	if cfg.Secret != "" {
+		headers[webhookSignatureHeader] = signWebhookBody(cfg.Secret, body)
+	}
+
+	if d.config.DryRun {
+		return writeDryRunPayload(d.config.DryRunOutput, &webhookRequest{URL: cfg.URL, Headers: redactHeaders(headers), Body: string(body)})
+	}
+
+	retries := defaultWebhookRetries
+	if cfg.Retries != nil {
+		retries = *cfg.Retries
+	}
+
+	delay := d.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := d.post(ctx, cfg.URL, headers, body)
+		if err == nil {
+			log.Println("Successfully sent merge request summary to webhook.")
+			return nil
+		}
+		if !retry || attempt >= retries {
+			return err
+		}
+
+		log.Printf("Error posting to webhook, retrying in %s: %v", delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// post sends a single request and reports whether it is worth retrying on failure.
+func (d *webhookDestination) post(ctx context.Context, url string, headers map[string]string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		return resp.StatusCode >= 500, err
+	}
+	return false, nil
+}
+
+// signWebhookBody returns the HMAC-SHA256 signature of the body in the "sha256=<hex>" form.
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookTemplateFuncs extends summary template helpers with json, which
+// encodes any value as JSON for embedding in the body.
+func webhookTemplateFuncs(now time.Time) template.FuncMap {
+	funcs := templateFuncs(now, false, catalogs[defaultLanguage])
+	funcs["json"] = func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+	return funcs
+}
+
+// loadWebhookTemplate parses the body template configured either inline or as a file.
+// It returns nil when neither is configured.
+func loadWebhookTemplate(cfg ConfigWebhook) (*template.Template, error) {
+	if cfg.Template != "" && cfg.TemplateFile != "" {
+		return nil, fmt.Errorf("template and template_file are mutually exclusive")
+	}
+
+	text, name := cfg.Template, "webhook"
+	if cfg.TemplateFile != "" {
+		data, err := os.ReadFile(cfg.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading template file: %w", err)
+		}
+		text, name = string(data), cfg.TemplateFile
+	}
+
+	if text == "" {
+		return nil, nil
+	}
+	return template.New(name).Funcs(webhookTemplateFuncs(time.Time{})).Parse(text)
+}
+
+// renderWebhookBody renders the document with the template, or encodes it as JSON if tmpl is nil.
+func renderWebhookBody(tmpl *template.Template, doc *WebhookDocument, now time.Time) ([]byte, error) {
+	if tmpl == nil {
+		return json.Marshal(doc)
+	}
+
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(webhookTemplateFuncs(now)).Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
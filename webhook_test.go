@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDestination(t *testing.T) {
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	mrs := testMergeRequestsForExport()
+
+	t.Run("posts signed document", func(t *testing.T) {
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, signWebhookBody("secret", body), r.Header.Get("X-Mergentle-Signature"))
+		}))
+		defer server.Close()
+
+		config := &Config{Webhook: ConfigWebhook{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Secret:  "secret",
+		}}
+		require.NoError(t, newWebhookDestination(config).Send(context.Background(), mrs, now))
+
+		var doc WebhookDocument
+		require.NoError(t, json.Unmarshal(body, &doc))
+		assert.Equal(t, "merge_requests.digest", doc.Event)
+		assert.Equal(t, 2, doc.Count)
+		assert.Equal(t, map[string]int{"needs_review": 1, "waiting_on_author": 1, "ready_to_merge": 0}, doc.States)
+		assert.Equal(t, "group/alpha", doc.MergeRequests[0].Project)
+	})
+
+	t.Run("renders template", func(t *testing.T) {
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		config := &Config{Webhook: ConfigWebhook{URL: server.URL}}
+		var err error
+		config.webhookTemplate, err = loadWebhookTemplate(ConfigWebhook{
+			Template: `{"summary": {{ json (printf "%d open, oldest %d days" .Count (ageDays (index .MergeRequests 0).CreatedAt)) }}}`,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, newWebhookDestination(config).Send(context.Background(), mrs, now))
+		assert.JSONEq(t, `{"summary": "2 open, oldest 4 days"}`, string(body))
+	})
+
+	t.Run("dry run redacts headers", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "dry-run.json")
+		config := &Config{DryRun: true, DryRunOutput: output, Webhook: ConfigWebhook{
+			URL:     "https://example.com/hook",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Secret:  "secret",
+		}}
+		require.NoError(t, newWebhookDestination(config).Send(context.Background(), mrs, now))
+
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "Bearer token")
+		assert.NotContains(t, string(data), "sha256=")
+
+		var req webhookRequest
+		require.NoError(t, json.Unmarshal(data, &req))
+		assert.Equal(t, map[string]string{
+			"Content-Type":          "application/json",
+			"Authorization":         "[redacted]",
+			"X-Mergentle-Signature": "[redacted]",
+		}, req.Headers)
+		assert.Contains(t, req.Body, "merge_requests.digest")
+	})
+
+	t.Run("retries on server errors", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer server.Close()
+
+		destination := newWebhookDestination(&Config{Webhook: ConfigWebhook{URL: server.URL}})
+		destination.retryDelay = 0
+
+		require.NoError(t, destination.Send(context.Background(), mrs, now))
+		assert.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		retries := 1
+		destination := newWebhookDestination(&Config{Webhook: ConfigWebhook{URL: server.URL, Retries: &retries}})
+		destination.retryDelay = 0
+
+		assert.ErrorContains(t, destination.Send(context.Background(), mrs, now), "unexpected status 503 Service Unavailable")
+		assert.EqualValues(t, 2, attempts.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			http.Error(w, "bad payload", http.StatusUnprocessableEntity)
+		}))
+		defer server.Close()
+
+		destination := newWebhookDestination(&Config{Webhook: ConfigWebhook{URL: server.URL}})
+		destination.retryDelay = 0
+
+		assert.ErrorContains(t, destination.Send(context.Background(), mrs, now), "unexpected status 422 Unprocessable Entity: bad payload")
+		assert.EqualValues(t, 1, attempts.Load())
+	})
+}
+
+func TestSignWebhookBody(t *testing.T) {
+	// Reference value computed with: printf '{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=77325902caca812dc259733aacd046b73817372c777b8d95b402647474516e13", signWebhookBody("secret", []byte("{}")))
+}
+
+func TestLoadWebhookTemplate(t *testing.T) {
+	tmpl, err := loadWebhookTemplate(ConfigWebhook{})
+	assert.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	_, err = loadWebhookTemplate(ConfigWebhook{Template: "{}", TemplateFile: "body.tmpl"})
+	assert.ErrorContains(t, err, "mutually exclusive")
+
+	_, err = loadWebhookTemplate(ConfigWebhook{Template: "{{ .Count"})
+	assert.Error(t, err)
+}